@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// printAPIResourcesUsage prints usage information for the resources command
+func printAPIResourcesUsage() {
+	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo resources [flags]
+
+List the resource types served by the cluster, like kubectl api-resources: name, short names,
+group/version, whether they are namespaced, kind and verbs. Each group is shown in its preferred version.
+Not to be confused with 'scheduling resources', which lists container requests and limits.
+
+Examples:
+  kubectl getinfo resources                              # Table of all resource types
+  kubectl getinfo resources -o json                      # Machine-readable list
+  kubectl getinfo resources --include-unavailable-groups # Retry API groups that fail discovery
+
+Flags:
+  -o, --output <format>            Output format: json, yaml, table. Default: table
+      --context <name>             Name of the kubeconfig context to use
+      --include-unavailable-groups Retry API groups that fail discovery (e.g. metrics-server)
+  -q, --quiet                      Suppress warnings
+  -h, --help                       Show help
+`)
+}
+
+// handleAPIResources runs the resources command
+func handleAPIResources(args []string) {
+	if containsHelpFlag(args) {
+		printAPIResourcesUsage()
+		os.Exit(0)
+	}
+
+	fs := flag.NewFlagSet("resources", flag.ContinueOnError)
+	var outputFormat, kubeContext string
+	var includeUnavailableGroups, quiet bool
+	fs.StringVar(&outputFormat, "o", "table", "output format")
+	fs.StringVar(&outputFormat, "output", "table", "output format")
+	fs.StringVar(&kubeContext, "context", "", "name of the kubeconfig context to use")
+	fs.BoolVar(&includeUnavailableGroups, "include-unavailable-groups", false, "retry API groups that fail discovery")
+	fs.BoolVar(&quiet, "q", false, "suppress warnings")
+	fs.BoolVar(&quiet, "quiet", false, "suppress warnings")
+	if err := fs.Parse(preprocessArgs(args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: resources takes no arguments, got '%s'\n", strings.Join(fs.Args(), " "))
+		os.Exit(1)
+	}
+	outputFormat = strings.ToLower(outputFormat)
+	if outputFormat != "json" && outputFormat != "yaml" && outputFormat != "table" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
+		os.Exit(1)
+	}
+
+	restConfig, err := getKubeconfig(kubeContext, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting kubeconfig: %v\n", err)
+		os.Exit(1)
+	}
+	apiResourceLists, failedGroups, err := discoverPreferredResources(restConfig, includeUnavailableGroups)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(failedGroups) > 0 && !quiet {
+		fmt.Fprintf(os.Stderr, "Warning: discovery failed for %s, their resources are not listed\n", strings.Join(failedGroups, ", "))
+	}
+
+	printAPIResources(os.Stdout, apiResourceInfos(apiResourceLists), outputFormat)
+}
+
+// apiResourceInfos flattens discovered resource lists into one entry per resource type
+// Subresources (pods/log, deployments/scale) are left out. Entries are sorted by group, the core group
+// first, then by name.
+func apiResourceInfos(apiResourceLists []*metav1.APIResourceList) []APIResourceInfo {
+	type groupedInfo struct {
+		group string
+		info  APIResourceInfo
+	}
+
+	var grouped []groupedInfo
+	for _, apiResourceList := range apiResourceLists {
+		if apiResourceList == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, apiResource := range apiResourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") {
+				continue
+			}
+			grouped = append(grouped, groupedInfo{
+				group: gv.Group,
+				info: APIResourceInfo{
+					Name:       apiResource.Name,
+					ShortNames: apiResource.ShortNames,
+					APIVersion: apiResourceList.GroupVersion,
+					Namespaced: apiResource.Namespaced,
+					Kind:       apiResource.Kind,
+					Verbs:      apiResource.Verbs,
+				},
+			})
+		}
+	}
+
+	sort.SliceStable(grouped, func(i, j int) bool {
+		if grouped[i].group != grouped[j].group {
+			return grouped[i].group < grouped[j].group
+		}
+		return grouped[i].info.Name < grouped[j].info.Name
+	})
+	infos := make([]APIResourceInfo, 0, len(grouped))
+	for _, g := range grouped {
+		infos = append(infos, g.info)
+	}
+	return infos
+}
+
+// printAPIResources outputs the resource types in the requested format (json, yaml or table)
+func printAPIResources(w io.Writer, resources []APIResourceInfo, outputFormat string) {
+	switch outputFormat {
+	case "json":
+		if err := writeJSON(w, APIResources{Resources: resources}, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(w, APIResources{Resources: resources}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		defer tw.Flush()
+
+		fmt.Fprintf(tw, "NAME\tSHORTNAMES\tAPIVERSION\tNAMESPACED\tKIND\tVERBS\n")
+		fmt.Fprintf(tw, "----\t----------\t----------\t----------\t----\t-----\n")
+		for _, r := range resources {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\n", r.Name, strings.Join(r.ShortNames, ","), r.APIVersion, r.Namespaced, r.Kind, strings.Join(r.Verbs, ","))
+		}
+	}
+}
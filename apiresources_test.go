@@ -0,0 +1,37 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAPIResourceInfos(t *testing.T) {
+	lists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", ShortNames: []string{"deploy"}, Namespaced: true, Kind: "Deployment", Verbs: metav1.Verbs{"get", "list"}},
+				{Name: "deployments/scale", Namespaced: true, Kind: "Scale", Verbs: metav1.Verbs{"get", "update"}},
+			},
+		},
+		nil,
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", ShortNames: []string{"po"}, Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"get", "list"}},
+				{Name: "namespaces", ShortNames: []string{"ns"}, Kind: "Namespace", Verbs: metav1.Verbs{"get"}},
+			},
+		},
+	}
+
+	want := []APIResourceInfo{
+		{Name: "namespaces", ShortNames: []string{"ns"}, APIVersion: "v1", Kind: "Namespace", Verbs: []string{"get"}},
+		{Name: "pods", ShortNames: []string{"po"}, APIVersion: "v1", Namespaced: true, Kind: "Pod", Verbs: []string{"get", "list"}},
+		{Name: "deployments", ShortNames: []string{"deploy"}, APIVersion: "apps/v1", Namespaced: true, Kind: "Deployment", Verbs: []string{"get", "list"}},
+	}
+	if got := apiResourceInfos(lists); !reflect.DeepEqual(got, want) {
+		t.Errorf("apiResourceInfos() = %+v, want %+v", got, want)
+	}
+}
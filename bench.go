@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+)
+
+// benchResult holds the List timings of one client (hidden bench command)
+type benchResult struct {
+	Client string
+	Items  int
+	Min    time.Duration
+	Avg    time.Duration
+	Max    time.Duration
+}
+
+// printBenchUsage prints usage information for the hidden bench command
+func printBenchUsage() {
+	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo bench list <resource-type> [flags]
+
+Time List requests with the full dynamic client and with the metadata-only client, which the labels,
+annotations, owner, revision and finalizers commands use, and print a comparison. For maintainers
+checking the metadata-only fast path, the output format may change.
+
+Examples:
+  kubectl getinfo bench list pods -A                    # Compare listing all pods
+  kubectl getinfo bench list configmaps --iterations 20 # More iterations for steadier numbers
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+      --iterations <n>             List requests per client. Default: 5
+      --context <name>             Name of the kubeconfig context to use
+  -h, --help                       Show help
+`)
+}
+
+// handleBench runs the hidden bench command
+func handleBench(args []string) {
+	if len(args) == 0 || containsHelpFlag(args) {
+		printBenchUsage()
+		os.Exit(0)
+	}
+	if args[0] != "list" {
+		fmt.Fprintf(os.Stderr, "Error: unknown bench '%s', only 'list' is supported\n", args[0])
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	var namespace, kubeContext string
+	var allNamespaces bool
+	var iterations int
+	fs.StringVar(&namespace, "n", "", "namespace")
+	fs.StringVar(&namespace, "namespace", "", "namespace")
+	fs.BoolVar(&allNamespaces, "A", false, "all namespaces")
+	fs.BoolVar(&allNamespaces, "all-namespaces", false, "all namespaces")
+	fs.IntVar(&iterations, "iterations", 5, "list requests per client")
+	fs.StringVar(&kubeContext, "context", "", "name of the kubeconfig context to use")
+
+	// The resource type may come before or after the flags
+	var positional []string
+	rest := preprocessArgs(args[1:])
+	for len(rest) > 0 {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(1)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: bench list requires exactly one resource type, got %d\n", len(positional))
+		os.Exit(1)
+	}
+	if iterations < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --iterations must be at least 1\n")
+		os.Exit(1)
+	}
+
+	restConfig, err := getKubeconfig(kubeContext, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting kubeconfig: %v\n", err)
+		os.Exit(1)
+	}
+	resources, err := resolveGVRs(positional, restConfig, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(errorExitCode(err, false))
+	}
+	resource := resources[0]
+	if allNamespaces || !resource.Namespaced {
+		namespace = ""
+	} else if namespace == "" {
+		namespace = getCurrentNamespace(kubeContext)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating dynamic client: %v\n", err)
+		os.Exit(1)
+	}
+	metadataClient, err := metadata.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating metadata client: %v\n", err)
+		os.Exit(1)
+	}
+
+	clients := []struct {
+		name   string
+		client resourceClient
+	}{
+		{"dynamic", dynamicResourceClient{client: dynamicClient}},
+		{"metadata", metadataResourceClient{client: metadataClient}},
+	}
+	var results []benchResult
+	for _, c := range clients {
+		result, err := timeList(context.Background(), c.client, resource.GVR, namespace, iterations)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing %s with the %s client: %v\n", resource.GVR.Resource, c.name, err)
+			os.Exit(1)
+		}
+		result.Client = c.name
+		results = append(results, result)
+	}
+	printBenchResults(os.Stdout, results)
+}
+
+// timeList lists the resources iterations times and returns the fastest, average and slowest request
+func timeList(ctx context.Context, client resourceClient, gvr schema.GroupVersionResource, namespace string, iterations int) (benchResult, error) {
+	var result benchResult
+	var total time.Duration
+	for i := 0; i < iterations; i++ {
+		start := time.Now()
+		items, err := client.list(ctx, gvr, namespace, metav1.ListOptions{})
+		elapsed := time.Since(start)
+		if err != nil {
+			return benchResult{}, err
+		}
+
+		result.Items = len(items)
+		total += elapsed
+		if i == 0 || elapsed < result.Min {
+			result.Min = elapsed
+		}
+		if elapsed > result.Max {
+			result.Max = elapsed
+		}
+	}
+	result.Avg = total / time.Duration(iterations)
+	return result, nil
+}
+
+// printBenchResults writes one row per client, and the average speedup of the others over the first one
+func printBenchResults(w io.Writer, results []benchResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CLIENT\tITEMS\tMIN\tAVG\tMAX\n")
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", result.Client, result.Items,
+			result.Min.Round(time.Microsecond), result.Avg.Round(time.Microsecond), result.Max.Round(time.Microsecond))
+	}
+	tw.Flush()
+
+	if len(results) < 2 {
+		return
+	}
+	baseline := results[0]
+	for _, result := range results[1:] {
+		if result.Avg <= 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\nAverage speedup of %s over %s: %.1fx\n", result.Client, baseline.Client, float64(baseline.Avg)/float64(result.Avg))
+	}
+}
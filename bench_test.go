@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestTimeList(t *testing.T) {
+	client := newFakeDynamicClient(newTestPod("default", "web"), newTestPod("default", "api"), newTestPod("other", "db"))
+	lists := 0
+	client.PrependReactor("list", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return false, nil, nil
+	})
+
+	result, err := timeList(context.Background(), dynamicResourceClient{client: client}, testPodGVR, "default", 3)
+	if err != nil {
+		t.Fatalf("timeList() error = %v", err)
+	}
+	if lists != 3 {
+		t.Errorf("timeList() made %d list requests, want 3", lists)
+	}
+	if result.Items != 2 {
+		t.Errorf("timeList() items = %d, want 2", result.Items)
+	}
+	if result.Min > result.Avg || result.Avg > result.Max {
+		t.Errorf("timeList() = %+v, want min <= avg <= max", result)
+	}
+}
+
+func TestPrintBenchResults(t *testing.T) {
+	var buf bytes.Buffer
+	printBenchResults(&buf, []benchResult{
+		{Client: "dynamic", Items: 100, Min: 8 * time.Millisecond, Avg: 10 * time.Millisecond, Max: 12 * time.Millisecond},
+		{Client: "metadata", Items: 100, Min: 3 * time.Millisecond, Avg: 4 * time.Millisecond, Max: 5 * time.Millisecond},
+	})
+
+	out := buf.String()
+	for _, want := range []string{"CLIENT", "dynamic   100", "metadata  100", "Average speedup of metadata over dynamic: 2.5x"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("printBenchResults() output is missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/dynamic"
 )
 
 // printCompletionUsage prints usage for the completion command
 func printCompletionUsage() {
-	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo completion <shell>
+	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo completion <shell> [--no-descriptions]
 
 Generate shell completion scripts for kubectl-getinfo.
 
@@ -16,6 +24,9 @@ Supported shells:
   zsh     Generate zsh completion script
   fish    Generate fish completion script
 
+Flags:
+      --no-descriptions    Complete plain words, without descriptions (zsh, fish)
+
 Examples:
   # Bash (add to ~/.bashrc)
   source <(kubectl-getinfo completion bash)
@@ -26,23 +37,71 @@ Examples:
   # Fish (add to ~/.config/fish/config.fish)
   kubectl-getinfo completion fish | source
 
+  # Zsh without descriptions next to each candidate
+  source <(kubectl-getinfo completion zsh --no-descriptions)
+
   # Or save to a file:
   kubectl-getinfo completion bash > /etc/bash_completion.d/kubectl-getinfo
 `)
 }
 
+// completionNamespaceLimit is the default of __list-namespaces --limit, the completion scripts are built with the same cap
+const completionNamespaceLimit = 500
+
+// listNamespacesTimeout bounds __list-namespaces, completion gives up rather than hanging the shell
+const listNamespacesTimeout = 3 * time.Second
+
+// handleListNamespaces prints namespace names one per line for the completion scripts (hidden __list-namespaces command)
+func handleListNamespaces(args []string) {
+	fs := flag.NewFlagSet("__list-namespaces", flag.ContinueOnError)
+	limit := fs.Int64("limit", completionNamespaceLimit, "maximum number of namespaces to print")
+	kubeContext := fs.String("context", "", "name of the kubeconfig context to use")
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	if *limit < 1 {
+		fmt.Fprintf(os.Stderr, "Error: --limit must be at least 1\n")
+		os.Exit(1)
+	}
+
+	restConfig, err := getKubeconfig(*kubeContext, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting kubeconfig: %v\n", err)
+		os.Exit(1)
+	}
+	restConfig.Timeout = listNamespacesTimeout
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating dynamic client: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listNamespacesTimeout)
+	defer cancel()
+	names, err := listNamespaceNames(ctx, client, *limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+}
+
 // generateBashCompletion generates bash completion script
-func generateBashCompletion() {
+// Bash completions are plain word lists, so noDescriptions doesn't change the script
+func generateBashCompletion(noDescriptions bool) {
+	namespaceLimit := strconv.Itoa(completionNamespaceLimit)
 	fmt.Print(`# bash completion for kubectl-getinfo
 
 _kubectl_getinfo_completions() {
     local cur prev words cword
     _init_completion || return
 
-    local commands="labels annotations owner scheduling completion"
+    local commands="labels annotations owner pdb command lifecycle revision identity replicas service finalizers network hooks volumes readiness env scheduling resources snapshot snapshot-diff completion"
     local scheduling_subcommands="tolerations affinity nodeselector resources topology priority runtime"
     local resource_types="pods po deployments deploy services svc nodes no configmaps cm secrets sec statefulsets sts daemonsets ds replicasets rs ingresses ing jobs cronjobs cj persistentvolumes pv persistentvolumeclaims pvc namespaces ns serviceaccounts sa endpoints ep events ev networkpolicies netpol"
-    local output_formats="json yaml table"
+    local output_formats="json jsonl yaml table csv tsv name otel jsonpath= go-template="
 
     # Count non-flag arguments
     local args=()
@@ -67,12 +126,24 @@ _kubectl_getinfo_completions() {
 
     # Handle completion command
     if [[ "$cmd" == "completion" ]]; then
-        if [[ ${#args[@]} -eq 1 ]]; then
+        if [[ "$cur" == -* ]]; then
+            COMPREPLY=($(compgen -W "--no-descriptions -h --help" -- "$cur"))
+        elif [[ ${#args[@]} -eq 1 ]]; then
             COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
         fi
         return
     fi
 
+    # Handle resources command, it only takes flags
+    if [[ "$cmd" == "resources" ]]; then
+        if [[ "$prev" == "-o" || "$prev" == "--output" ]]; then
+            COMPREPLY=($(compgen -W "json yaml table" -- "$cur"))
+        elif [[ "$cur" == -* ]]; then
+            COMPREPLY=($(compgen -W "-o --output --context --include-unavailable-groups -q --quiet -h --help" -- "$cur"))
+        fi
+        return
+    fi
+
     # Handle scheduling command with subcommands
     if [[ "$cmd" == "scheduling" ]]; then
         if [[ ${#args[@]} -eq 1 ]]; then
@@ -106,7 +177,7 @@ _kubectl_getinfo_completions() {
         fi
     fi
 
-    # For other commands (labels, annotations, owner) or after resource type
+    # For other commands (labels, annotations, owner, pdb, command, lifecycle, revision, identity, replicas, service, finalizers, network, hooks, volumes, readiness, env) or after resource type
     if [[ ${#args[@]} -eq 1 ]]; then
         # After command, suggest resource types
         if [[ "$cur" == -* ]]; then
@@ -130,9 +201,9 @@ _kubectl_getinfo_completions() {
             return
             ;;
         -n|--namespace)
-            # Try to get namespaces from kubectl
+            # Ask the plugin for a capped list, so large clusters don't stall the shell
             local namespaces
-            if namespaces=$(kubectl get namespaces -o jsonpath='{.items[*].metadata.name}' 2>/dev/null); then
+            if namespaces=$(kubectl-getinfo __list-namespaces --limit ` + namespaceLimit + ` 2>/dev/null); then
                 COMPREPLY=($(compgen -W "$namespaces" -- "$cur"))
             fi
             return
@@ -151,8 +222,9 @@ fi
 }
 
 // generateZshCompletion generates zsh completion script
-func generateZshCompletion() {
-	fmt.Print(`#compdef kubectl-getinfo
+func generateZshCompletion(noDescriptions bool) {
+	namespaceLimit := strconv.Itoa(completionNamespaceLimit)
+	script := `#compdef kubectl-getinfo
 
 # zsh completion for kubectl-getinfo
 
@@ -164,7 +236,23 @@ _kubectl_getinfo() {
         'labels:List labels of resources'
         'annotations:List annotations of resources'
         'owner:List ownerReferences of resources'
+        'pdb:List PodDisruptionBudgets protecting resources'
+        'command:List container commands and args'
+        'lifecycle:List restartPolicy and termination/deadline settings'
+        'revision:List rollout revision and change-cause'
+        'identity:List service account and imagePullSecrets'
+        'replicas:List desired, current, ready and available replicas'
+        'service:List Service type, cluster IP and selector'
+        'finalizers:List finalizers and deletionTimestamp'
+        'network:List dnsPolicy, dnsConfig and hostAliases'
+        'hooks:List postStart and preStop hooks'
+        'volumes:List volumes and StatefulSet volumeClaimTemplates'
+        'readiness:List readiness gates and whether they are satisfied'
+        'env:List container environment variables and envFrom sources'
         'scheduling:List scheduling-related fields'
+        'resources:List the resource types served by the cluster'
+        'snapshot:Save the output of a command to a file'
+        'snapshot-diff:Compare two snapshot files'
         'completion:Generate shell completion scripts'
     )
 
@@ -230,7 +318,7 @@ _kubectl_getinfo() {
                 scheduling)
                     _describe -t scheduling-subcommands 'subcommand or resource' scheduling_subcommands resource_types
                     ;;
-                labels|annotations|owner)
+                labels|annotations|owner|pdb|command|lifecycle|revision|identity|replicas|service|finalizers|network|hooks|volumes|readiness|env)
                     _describe -t resources 'resource type' resource_types
                     ;;
             esac
@@ -247,7 +335,7 @@ _kubectl_getinfo() {
                             ;;
                     esac
                     ;;
-                labels|annotations|owner)
+                labels|annotations|owner|pdb|command|lifecycle|revision|identity|replicas|service|finalizers|network|hooks|volumes|readiness|env)
                     _kubectl_getinfo_complete_with_resources $line[2]
                     ;;
                 *)
@@ -259,7 +347,7 @@ _kubectl_getinfo() {
             # Determine the resource type from the command line
             local resource_type=""
             case $line[1] in
-                labels|annotations|owner)
+                labels|annotations|owner|pdb|command|lifecycle|revision|identity|replicas|service|finalizers|network|hooks|volumes|readiness|env)
                     resource_type=$line[2]
                     ;;
                 scheduling)
@@ -285,7 +373,7 @@ _kubectl_getinfo_complete_with_resources() {
     local cmd=${words[2]}
     
     case $cmd in
-        labels|annotations|owner)
+        labels|annotations|owner|pdb|command|lifecycle|revision|identity|replicas|service|finalizers|network|hooks|volumes|readiness|env)
             resource_type=${words[3]}
             ;;
         scheduling)
@@ -334,13 +422,13 @@ _kubectl_getinfo_flags() {
 }
 
 _kubectl_getinfo_output() {
-    local -a formats=('json:JSON format' 'yaml:YAML format' 'table:Table format')
+    local -a formats=('json:JSON format' 'jsonl:One JSON object per line' 'yaml:YAML format' 'table:Table format' 'csv:Comma-separated values' 'tsv:Tab-separated values' 'name:Type and name' 'otel:OpenTelemetry resource attributes' 'jsonpath=:JSONPath template' 'go-template=:Go template')
     _describe -t formats 'output format' formats
 }
 
 _kubectl_getinfo_namespaces() {
     local -a namespaces
-    namespaces=(${(f)"$(kubectl get namespaces -o jsonpath='{range .items[*]}{.metadata.name}{"\n"}{end}' 2>/dev/null)"})
+    namespaces=(${(f)"$(kubectl-getinfo __list-namespaces --limit ` + namespaceLimit + ` 2>/dev/null)"})
     _describe -t namespaces 'namespace' namespaces
 }
 
@@ -351,7 +439,7 @@ _kubectl_getinfo_resource_names() {
     local cmd=${words[2]}
     
     case $cmd in
-        labels|annotations|owner)
+        labels|annotations|owner|pdb|command|lifecycle|revision|identity|replicas|service|finalizers|network|hooks|volumes|readiness|env)
             resource_type=${words[3]}
             ;;
         scheduling)
@@ -415,12 +503,17 @@ _kubectl_getinfo_resource_names() {
 }
 
 compdef _kubectl_getinfo kubectl-getinfo
-`)
+`
+	if noDescriptions {
+		script = stripZshDescriptions(script)
+	}
+	fmt.Print(script)
 }
 
 // generateFishCompletion generates fish completion script
-func generateFishCompletion() {
-	fmt.Print(`# fish completion for kubectl-getinfo
+func generateFishCompletion(noDescriptions bool) {
+	namespaceLimit := strconv.Itoa(completionNamespaceLimit)
+	script := `# fish completion for kubectl-getinfo
 
 # Disable file completion by default
 complete -c kubectl-getinfo -f
@@ -429,11 +522,28 @@ complete -c kubectl-getinfo -f
 complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "labels" -d "List labels of resources"
 complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "annotations" -d "List annotations of resources"
 complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "owner" -d "List ownerReferences of resources"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "pdb" -d "List PodDisruptionBudgets protecting resources"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "command" -d "List container commands and args"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "lifecycle" -d "List restartPolicy and termination/deadline settings"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "revision" -d "List rollout revision and change-cause"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "identity" -d "List service account and imagePullSecrets"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "replicas" -d "List desired, current, ready and available replicas"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "service" -d "List Service type, cluster IP and selector"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "finalizers" -d "List finalizers and deletionTimestamp"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "network" -d "List dnsPolicy, dnsConfig and hostAliases"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "hooks" -d "List postStart and preStop hooks"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "volumes" -d "List volumes and StatefulSet volumeClaimTemplates"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "readiness" -d "List readiness gates and whether they are satisfied"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "env" -d "List container environment variables and envFrom sources"
 complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "scheduling" -d "List scheduling-related fields"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "resources" -d "List the resource types served by the cluster"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "snapshot" -d "Save the output of a command to a file"
+complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "snapshot-diff" -d "Compare two snapshot files"
 complete -c kubectl-getinfo -n "__fish_use_subcommand" -a "completion" -d "Generate shell completion scripts"
 
 # Completion subcommand
 complete -c kubectl-getinfo -n "__fish_seen_subcommand_from completion" -a "bash zsh fish"
+complete -c kubectl-getinfo -n "__fish_seen_subcommand_from completion" -l no-descriptions -d "Complete without descriptions"
 
 # Scheduling subcommands
 complete -c kubectl-getinfo -n "__fish_seen_subcommand_from scheduling; and not __fish_seen_subcommand_from tolerations affinity nodeselector resources topology priority runtime" -a "tolerations" -d "List only tolerations"
@@ -447,20 +557,44 @@ complete -c kubectl-getinfo -n "__fish_seen_subcommand_from scheduling; and not
 # Resource types (for all commands)
 set -l resource_types pods po deployments deploy services svc nodes no configmaps cm secrets sec statefulsets sts daemonsets ds replicasets rs ingresses ing jobs cronjobs cj persistentvolumes pv persistentvolumeclaims pvc namespaces ns serviceaccounts sa endpoints ep events ev networkpolicies netpol
 
-for cmd in labels annotations owner
+for cmd in labels annotations owner pdb command lifecycle revision identity replicas service finalizers network hooks volumes readiness env
     complete -c kubectl-getinfo -n "__fish_seen_subcommand_from $cmd" -a "$resource_types"
 end
 
 complete -c kubectl-getinfo -n "__fish_seen_subcommand_from scheduling" -a "$resource_types"
 
 # Flags (for all commands except completion)
-complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s n -l namespace -d "Specify namespace" -x -a "(kubectl get namespaces -o jsonpath='{.items[*].metadata.name}' 2>/dev/null | string split ' ')"
+complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s n -l namespace -d "Specify namespace" -x -a "(kubectl-getinfo __list-namespaces --limit ` + namespaceLimit + ` 2>/dev/null)"
 complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s A -l all-namespaces -d "All namespaces"
 complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s l -l selector -d "Label selector"
-complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s o -l output -d "Output format" -x -a "json yaml table"
+complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s o -l output -d "Output format" -x -a "json jsonl yaml table csv tsv name otel jsonpath= go-template="
 complete -c kubectl-getinfo -n "not __fish_seen_subcommand_from completion" -s c -l color -d "Colorize JSON output"
 complete -c kubectl-getinfo -s h -l help -d "Show help"
-`)
+`
+	if noDescriptions {
+		script = stripFishDescriptions(script)
+	}
+	fmt.Print(script)
+}
+
+var (
+	// zshDescribedWord matches 'word:description' entries of the arrays passed to _describe
+	zshDescribedWord = regexp.MustCompile(`'([a-z][\w=-]*):[^':]*'`)
+	// zshOptionDescription matches the [description] of an _arguments option spec
+	zshOptionDescription = regexp.MustCompile(`'(-{1,2}[\w-]+)\[[^\]]*\]`)
+	// fishDescription matches the -d "description" of a complete command
+	fishDescription = regexp.MustCompile(` -d "[^"]*"`)
+)
+
+// stripZshDescriptions turns the zsh script into a description-free variant
+func stripZshDescriptions(script string) string {
+	script = zshDescribedWord.ReplaceAllString(script, "'$1'")
+	return zshOptionDescription.ReplaceAllString(script, "'$1")
+}
+
+// stripFishDescriptions turns the fish script into a description-free variant
+func stripFishDescriptions(script string) string {
+	return fishDescription.ReplaceAllString(script, "")
 }
 
 // handleCompletion handles the completion command
@@ -470,14 +604,35 @@ func handleCompletion(args []string) {
 		os.Exit(0)
 	}
 
-	shell := args[0]
+	// The flag may come before or after the shell name
+	var shell string
+	noDescriptions := false
+	for _, arg := range args {
+		switch {
+		case arg == "--no-descriptions":
+			noDescriptions = true
+		case isHelpFlag(arg):
+			printCompletionUsage()
+			os.Exit(0)
+		case strings.HasPrefix(arg, "-"):
+			fmt.Fprintf(os.Stderr, "Error: unknown flag '%s' for completion command\n", arg)
+			os.Exit(1)
+		case shell == "":
+			shell = arg
+		}
+	}
+	if shell == "" {
+		printCompletionUsage()
+		os.Exit(0)
+	}
+
 	switch shell {
 	case "bash":
-		generateBashCompletion()
+		generateBashCompletion(noDescriptions)
 	case "zsh":
-		generateZshCompletion()
+		generateZshCompletion(noDescriptions)
 	case "fish":
-		generateFishCompletion()
+		generateFishCompletion(noDescriptions)
 	default:
 		fmt.Fprintf(os.Stderr, "Error: unsupported shell '%s'. Supported shells: bash, zsh, fish\n", shell)
 		os.Exit(1)
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestStripZshDescriptions(t *testing.T) {
+	script := captureStdout(t, func() { generateZshCompletion(false) })
+	stripped := captureStdout(t, func() { generateZshCompletion(true) })
+
+	// Described words are 'word:Description', option specs are '--flag[Description]'
+	describedWords := regexp.MustCompile(`'([a-z][\w=-]*):[A-Z][^']*'`).FindAllStringSubmatch(script, -1)
+	optionSpecs := regexp.MustCompile(`'(-{1,2}[\w-]+)\[[^\]]*\]`).FindAllStringSubmatch(script, -1)
+	if len(describedWords) == 0 || len(optionSpecs) == 0 {
+		t.Fatal("zsh script has no described words or option specs to strip")
+	}
+
+	for _, match := range describedWords {
+		if strings.Contains(stripped, match[0]) {
+			t.Errorf("stripped zsh script still has the description %s", match[0])
+		}
+		if !strings.Contains(stripped, "'"+match[1]+"'") {
+			t.Errorf("stripped zsh script lost the word %s", match[1])
+		}
+	}
+	for _, match := range optionSpecs {
+		if strings.Contains(stripped, match[0]) {
+			t.Errorf("stripped zsh script still has the option spec %s", match[0])
+		}
+		if !strings.Contains(stripped, "'"+match[1]+"'") && !strings.Contains(stripped, "'"+match[1]+":") {
+			t.Errorf("stripped zsh script lost the flag %s", match[1])
+		}
+	}
+}
+
+func TestStripFishDescriptions(t *testing.T) {
+	script := captureStdout(t, func() { generateFishCompletion(false) })
+	stripped := captureStdout(t, func() { generateFishCompletion(true) })
+
+	if !strings.Contains(script, ` -d "`) {
+		t.Fatal("fish script has no descriptions to strip")
+	}
+	if strings.Contains(stripped, ` -d `) {
+		t.Error("stripped fish script still has -d arguments")
+	}
+
+	// Every command, subcommand and flag is still completed
+	completed := regexp.MustCompile(` -(?:a "[^"]*"|l [\w-]+|s \w)`)
+	want := completed.FindAllString(script, -1)
+	got := completed.FindAllString(stripped, -1)
+	if !equalStrings(got, want) {
+		t.Errorf("stripped fish script completes %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputEnvVar is the environment variable that overrides the default output format
+const outputEnvVar = "KUBECTL_GETINFO_OUTPUT"
+
+// Config represents the user configuration file (~/.config/kubectl-getinfo/config.yaml)
+// Every key is a default, the matching flag overrides it
+type Config struct {
+	Output string `yaml:"output"`
+	// Color is the default of -c, --color (turn it off for one run with --color=false)
+	Color bool `yaml:"color"`
+	// MaxColWidth is the default of --max-col-width, only applied to table output
+	MaxColWidth int `yaml:"maxColWidth"`
+	// ExcludeNamespaces is the default of --exclude-namespaces, only applied with -A
+	ExcludeNamespaces []string `yaml:"excludeNamespaces"`
+	// Concurrency is the default of --concurrency, the number of namespaces queried at once
+	Concurrency int `yaml:"concurrency"`
+}
+
+// getConfigPath returns the path of the user configuration file
+func getConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", "kubectl-getinfo", "config.yaml"), nil
+}
+
+// loadConfig reads the user configuration file
+// A missing configuration file is not an error and results in an empty config
+func loadConfig() (Config, error) {
+	var config Config
+
+	configPath, err := getConfigPath()
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("error reading config file %s: %v", configPath, err)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("error parsing config file %s: %v", configPath, err)
+	}
+	if config.MaxColWidth < 0 {
+		return config, fmt.Errorf("error in config file %s: maxColWidth must not be negative", configPath)
+	}
+	if config.Concurrency < 0 {
+		return config, fmt.Errorf("error in config file %s: concurrency must not be negative", configPath)
+	}
+
+	return config, nil
+}
+
+// getDefaultOutputFormat returns the output format used when -o is not passed
+// Precedence: KUBECTL_GETINFO_OUTPUT env var, then the config file, then the command default.
+// A configured table format is ignored for commands that don't support table output.
+func getDefaultOutputFormat(cmdType string, commandDefault string, config Config) string {
+	for _, format := range []string{os.Getenv(outputEnvVar), config.Output} {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "" {
+			continue
+		}
+		if format == "table" && !supportsTable(cmdType) {
+			continue
+		}
+		return format
+	}
+	return commandDefault
+}
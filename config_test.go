@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestConfig writes a config file under a temporary home directory
+func writeTestConfig(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".config", "kubectl-getinfo", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	writeTestConfig(t, "output: json\ncolor: true\nmaxColWidth: 40\nexcludeNamespaces: [kube-system, monitoring]\nconcurrency: 4\n")
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	want := Config{Output: "json", Color: true, MaxColWidth: 40, ExcludeNamespaces: []string{"kube-system", "monitoring"}, Concurrency: 4}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("loadConfig() = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(config, Config{}) {
+		t.Errorf("loadConfig() = %+v, want empty config", config)
+	}
+}
+
+func TestLoadConfigNegativeMaxColWidth(t *testing.T) {
+	writeTestConfig(t, "maxColWidth: -1\n")
+
+	if _, err := loadConfig(); err == nil {
+		t.Error("loadConfig() error = nil, want error for a negative maxColWidth")
+	}
+}
+
+func TestLoadConfigNegativeConcurrency(t *testing.T) {
+	writeTestConfig(t, "concurrency: -1\n")
+
+	if _, err := loadConfig(); err == nil {
+		t.Error("loadConfig() error = nil, want error for a negative concurrency")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// schedulingFieldDescriptions explains the scheduling fields in one line each (--explain)
+var schedulingFieldDescriptions = map[string]string{
+	"nodeSelector":              "Node labels the pod requires, it only runs on nodes that have all of them",
+	"nodeName":                  "Node the pod is bound to, set by the scheduler (or by hand to bypass it)",
+	"affinity":                  "Rules that attract the pod to nodes (nodeAffinity) or to and away from other pods (podAffinity, podAntiAffinity)",
+	"tolerations":               "Node taints the pod accepts, tainted nodes repel the pods that don't tolerate their taints",
+	"topologySpreadConstraints": "How evenly the pods are spread across zones, nodes or other topology domains",
+	"requests":                  "CPU and memory reserved per container, the pod only goes to nodes with that much unreserved",
+	"limits":                    "Most CPU and memory a container may use, CPU above it is throttled and memory above it gets the container killed",
+	"overhead":                  "Resources the RuntimeClass adds for the pod sandbox (e.g. Kata VMs), counted on top of the container requests",
+	"resourceClaims":            "Devices (e.g. GPUs) allocated through dynamic resource allocation, containers list the claims they use under resources.claims",
+	"schedulerName":             "Scheduler that places the pod, default-scheduler unless a custom scheduler is used",
+	"priorityClassName":         "PriorityClass the pod's priority comes from",
+	"priority":                  "Priority value, higher priority pods are scheduled first and may preempt lower priority ones",
+	"preemptionPolicy":          "Whether the pod may evict lower priority pods to make room (PreemptLowerPriority) or not (Never)",
+	"runtimeClassName":          "RuntimeClass choosing the container runtime handler (e.g. gVisor, Kata) and the nodes that provide it",
+	"hostNetwork":               "The pod uses the node's network, its ports are opened on the node itself",
+	"hostPID":                   "The pod sees the node's processes and can signal them",
+	"hostIPC":                   "The pod shares the node's IPC namespace (shared memory, semaphores)",
+}
+
+// schedulingExplainedFields lists the fields shown by each scheduling subcommand, in output order
+var schedulingExplainedFields = map[string][]string{
+	"": {"nodeSelector", "nodeName", "affinity", "tolerations", "topologySpreadConstraints", "requests", "limits", "overhead",
+		"schedulerName", "priorityClassName", "priority", "preemptionPolicy", "runtimeClassName", "hostNetwork", "hostPID", "hostIPC"},
+	"tolerations":  {"tolerations"},
+	"affinity":     {"affinity"},
+	"nodeselector": {"nodeSelector"},
+	"resources":    {"requests", "limits", "overhead", "resourceClaims"},
+	"topology":     {"topologySpreadConstraints"},
+	"priority":     {"priorityClassName", "priority", "preemptionPolicy"},
+	"runtime":      {"runtimeClassName", "hostNetwork", "hostPID", "hostIPC"},
+}
+
+// printSchedulingExplanation writes what each field of a scheduling subcommand means, below its output
+// asComments prefixes the lines with "# " so YAML output stays valid
+func printSchedulingExplanation(w io.Writer, subCommand string, asComments bool) {
+	prefix := ""
+	if asComments {
+		prefix = "# "
+	}
+
+	fmt.Fprintf(w, "\n%sWhat these fields mean:\n", prefix)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, field := range schedulingExplainedFields[subCommand] {
+		fmt.Fprintf(tw, "%s  %s\t%s\n", prefix, field, schedulingFieldDescriptions[field])
+	}
+	tw.Flush()
+}
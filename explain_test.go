@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSchedulingExplainedFieldsHaveDescriptions(t *testing.T) {
+	for subCommand, fields := range schedulingExplainedFields {
+		for _, field := range fields {
+			if schedulingFieldDescriptions[field] == "" {
+				t.Errorf("field %q of subcommand %q has no description", field, subCommand)
+			}
+		}
+	}
+}
+
+func TestPrintSchedulingExplanation(t *testing.T) {
+	var buf bytes.Buffer
+	printSchedulingExplanation(&buf, "priority", true)
+
+	lines := strings.Split(strings.TrimPrefix(buf.String(), "\n"), "\n")
+	want := []string{
+		"# What these fields mean:",
+		"#   priorityClassName  PriorityClass the pod's priority comes from",
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("line %d = %q, want %q", i, lines[i], line)
+		}
+	}
+	if got := strings.Count(buf.String(), "\n#   "); got != 3 {
+		t.Errorf("explained %d fields, want 3:\n%s", got, buf.String())
+	}
+}
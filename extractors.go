@@ -1,9 +1,113 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// itemExtractor fills the fields shown by a command into the output item of a resource
+type itemExtractor func(item unstructured.Unstructured, outputItem *OutputItem)
+
+// commandExtractors holds the extractor of every resource command except scheduling (see schedulingExtractors)
+// A command is known as soon as it is registered here, main.go adds what needs other objects
+// (PodDisruptionBudgets, namespace labels, rollout revisions, envFrom sources)
+var commandExtractors = map[string]itemExtractor{
+	"labels": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		labels := item.GetLabels()
+		outputItem.Labels = &labels
+	},
+	"annotations": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		annotations := item.GetAnnotations()
+		outputItem.Annotations = &annotations
+	},
+	"owner": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.OwnerReferences = extractOwnerReferences(item)
+		if controller := controllerOwner(outputItem.OwnerReferences); controller != nil {
+			outputItem.OwnerKind = controller.Kind
+			outputItem.OwnerName = controller.Name
+		}
+	},
+	// Matched against the PodDisruptionBudgets of the namespace in main.go
+	"pdb": func(item unstructured.Unstructured, outputItem *OutputItem) {},
+	"command": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Commands = extractContainerCommands(item)
+	},
+	"hooks": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Hooks = extractContainerHooks(item)
+	},
+	"volumes": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Volumes = extractVolumes(item)
+		outputItem.VolumeClaimTemplates = extractVolumeClaimTemplates(item)
+	},
+	"readiness": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.ReadinessGates = extractReadinessGates(item)
+	},
+	"env": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Env = extractContainerEnv(item)
+	},
+	"lifecycle": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Lifecycle = extractLifecycleInfo(item)
+	},
+	"revision": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Revision = extractRevisionInfo(item)
+	},
+	"identity": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Identity = extractIdentityInfo(item)
+	},
+	"replicas": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Replicas = extractReplicasInfo(item)
+	},
+	"service": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Service = extractServiceInfo(item)
+	},
+	"finalizers": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Finalizers = extractFinalizersInfo(item)
+	},
+	"network": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Network = extractNetworkInfo(item)
+	},
+}
+
+// schedulingExtractors holds the extractor of each scheduling subcommand, "" shows all scheduling fields
+var schedulingExtractors = map[string]itemExtractor{
+	"": func(item unstructured.Unstructured, outputItem *OutputItem) {
+		outputItem.Scheduling = extractSchedulingInfo(item)
+	},
+	"tolerations":  schedulingSubcommandExtractor("tolerations"),
+	"affinity":     schedulingSubcommandExtractor("affinity"),
+	"nodeselector": schedulingSubcommandExtractor("nodeselector"),
+	"resources":    schedulingSubcommandExtractor("resources"),
+	"topology":     schedulingSubcommandExtractor("topology"),
+	"priority":     schedulingSubcommandExtractor("priority"),
+	"runtime":      schedulingSubcommandExtractor("runtime"),
+}
+
+// schedulingSubcommandExtractor returns the extractor of a single scheduling field (see extractSchedulingSubcommand)
+func schedulingSubcommandExtractor(subCommand string) itemExtractor {
+	return func(item unstructured.Unstructured, outputItem *OutputItem) {
+		extractSchedulingSubcommand(item, outputItem, subCommand)
+	}
+}
+
+// lookupExtractor returns the extractor of a command, or of a scheduling subcommand
+func lookupExtractor(cmdType string, subCommand string) (itemExtractor, bool) {
+	if cmdType == "scheduling" {
+		extractor, ok := schedulingExtractors[subCommand]
+		return extractor, ok
+	}
+	extractor, ok := commandExtractors[cmdType]
+	return extractor, ok
+}
+
 // getPodSpecPath returns the path to the pod spec based on the resource kind
 func getPodSpecPath(item unstructured.Unstructured) []string {
 	kind := item.GetKind()
@@ -13,11 +117,16 @@ func getPodSpecPath(item unstructured.Unstructured) []string {
 		return []string{"spec"}
 	}
 
+	// CronJobs wrap a Job template, the pod spec is one level deeper
+	if kind == "CronJob" {
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+
 	// Para recursos com template (Deployments, StatefulSets, etc.)
 	// O spec do pod está em spec.template.spec
 	templateKinds := []string{
 		"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet",
-		"Job", "CronJob",
+		"Job",
 	}
 
 	for _, tk := range templateKinds {
@@ -30,6 +139,604 @@ func getPodSpecPath(item unstructured.Unstructured) []string {
 	return []string{"spec"}
 }
 
+// getPodLabels returns the labels of the pods managed by a resource
+// For Pods these are the resource's own labels, for workloads the labels of the pod template
+func getPodLabels(item unstructured.Unstructured) map[string]string {
+	specPath := getPodSpecPath(item)
+	if len(specPath) == 1 {
+		return item.GetLabels()
+	}
+
+	// The template metadata lives next to the template spec
+	metadataPath := append(append([]string{}, specPath[:len(specPath)-1]...), "metadata", "labels")
+	podLabels, _, _ := unstructured.NestedStringMap(item.Object, metadataPath...)
+	return podLabels
+}
+
+// extractPodDisruptionBudgets returns the PodDisruptionBudgets whose selector matches the pods of a resource
+func extractPodDisruptionBudgets(item unstructured.Unstructured, pdbs []unstructured.Unstructured) []PodDisruptionBudgetInfo {
+	matched := []PodDisruptionBudgetInfo{}
+	podLabels := labels.Set(getPodLabels(item))
+
+	for _, pdb := range pdbs {
+		// A PDB without selector selects no pods
+		selectorMap, found, _ := unstructured.NestedMap(pdb.Object, "spec", "selector")
+		if !found {
+			continue
+		}
+
+		var labelSelector metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, &labelSelector); err != nil {
+			continue
+		}
+
+		// An empty selector ({}) matches every pod in the namespace
+		selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		if err != nil || !selector.Matches(podLabels) {
+			continue
+		}
+
+		pdbInfo := PodDisruptionBudgetInfo{
+			Name: pdb.GetName(),
+		}
+
+		// minAvailable and maxUnavailable are IntOrString (e.g. 1 or "50%")
+		if minAvailable, found, _ := unstructured.NestedFieldNoCopy(pdb.Object, "spec", "minAvailable"); found {
+			pdbInfo.MinAvailable = minAvailable
+		}
+		if maxUnavailable, found, _ := unstructured.NestedFieldNoCopy(pdb.Object, "spec", "maxUnavailable"); found {
+			pdbInfo.MaxUnavailable = maxUnavailable
+		}
+
+		// Current status
+		pdbInfo.CurrentHealthy, _, _ = unstructured.NestedInt64(pdb.Object, "status", "currentHealthy")
+		pdbInfo.DesiredHealthy, _, _ = unstructured.NestedInt64(pdb.Object, "status", "desiredHealthy")
+		pdbInfo.DisruptionsAllowed, _, _ = unstructured.NestedInt64(pdb.Object, "status", "disruptionsAllowed")
+		pdbInfo.ExpectedPods, _, _ = unstructured.NestedInt64(pdb.Object, "status", "expectedPods")
+
+		matched = append(matched, pdbInfo)
+	}
+
+	return matched
+}
+
+// extractContainerCommands extracts the command and args of every container, init containers first
+// Containers without command/args are included since they run the image entrypoint
+func extractContainerCommands(item unstructured.Unstructured) []ContainerCommand {
+	specPath := getPodSpecPath(item)
+	var commands []ContainerCommand
+
+	for _, containerField := range []string{"initContainers", "containers"} {
+		containers, found, _ := unstructured.NestedSlice(item.Object, append(specPath, containerField)...)
+		if !found {
+			continue
+		}
+
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			containerName, _ := containerMap["name"].(string)
+			commands = append(commands, ContainerCommand{
+				Name:    containerName,
+				Init:    containerField == "initContainers",
+				Command: toStringSlice(containerMap["command"]),
+				Args:    toStringSlice(containerMap["args"]),
+			})
+		}
+	}
+
+	return commands
+}
+
+// extractContainerEnv extracts the env and envFrom entries of every container, init containers first
+func extractContainerEnv(item unstructured.Unstructured) []ContainerEnv {
+	specPath := getPodSpecPath(item)
+	var result []ContainerEnv
+
+	for _, containerField := range []string{"initContainers", "containers"} {
+		containers, found, _ := unstructured.NestedSlice(item.Object, append(specPath, containerField)...)
+		if !found {
+			continue
+		}
+
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			containerName, _ := containerMap["name"].(string)
+			containerEnv := ContainerEnv{Name: containerName, Init: containerField == "initContainers"}
+
+			env, _ := containerMap["env"].([]interface{})
+			for _, entry := range env {
+				entryMap, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				envVar := EnvVar{}
+				envVar.Name, _ = entryMap["name"].(string)
+				envVar.Value, _ = entryMap["value"].(string)
+				if valueFrom, ok := entryMap["valueFrom"].(map[string]interface{}); ok {
+					envVar.ValueFrom = formatEnvValueFrom(valueFrom)
+				}
+				containerEnv.Env = append(containerEnv.Env, envVar)
+			}
+
+			envFrom, _ := containerMap["envFrom"].([]interface{})
+			for _, entry := range envFrom {
+				entryMap, ok := entry.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				source := EnvFromSource{}
+				source.Prefix, _ = entryMap["prefix"].(string)
+				if ref, ok := entryMap["configMapRef"].(map[string]interface{}); ok {
+					source.Kind = "ConfigMap"
+					source.Name, _ = ref["name"].(string)
+					source.Optional, _ = ref["optional"].(bool)
+				} else if ref, ok := entryMap["secretRef"].(map[string]interface{}); ok {
+					source.Kind = "Secret"
+					source.Name, _ = ref["name"].(string)
+					source.Optional, _ = ref["optional"].(bool)
+				} else {
+					continue
+				}
+				containerEnv.EnvFrom = append(containerEnv.EnvFrom, source)
+			}
+
+			result = append(result, containerEnv)
+		}
+	}
+
+	return result
+}
+
+// formatEnvValueFrom renders where an env value comes from, e.g. "secretKeyRef:db-creds/password",
+// "configMapKeyRef:app-config/LOG_LEVEL", "fieldRef:metadata.name" or "resourceFieldRef:limits.cpu"
+func formatEnvValueFrom(valueFrom map[string]interface{}) string {
+	for _, refType := range []string{"secretKeyRef", "configMapKeyRef"} {
+		if ref, ok := valueFrom[refType].(map[string]interface{}); ok {
+			name, _ := ref["name"].(string)
+			key, _ := ref["key"].(string)
+			return refType + ":" + name + "/" + key
+		}
+	}
+	if ref, ok := valueFrom["fieldRef"].(map[string]interface{}); ok {
+		fieldPath, _ := ref["fieldPath"].(string)
+		return "fieldRef:" + fieldPath
+	}
+	if ref, ok := valueFrom["resourceFieldRef"].(map[string]interface{}); ok {
+		resource, _ := ref["resource"].(string)
+		return "resourceFieldRef:" + resource
+	}
+
+	// Sources added by newer Kubernetes versions are named as they are
+	for refType := range valueFrom {
+		return refType
+	}
+	return ""
+}
+
+// extractContainerHooks extracts the postStart and preStop hooks of every container, init containers first
+// Containers without hooks are included, so the ones missing a preStop hook stand out
+// (init containers only run hooks as sidecars, with restartPolicy: Always)
+func extractContainerHooks(item unstructured.Unstructured) []ContainerHooks {
+	specPath := getPodSpecPath(item)
+	var hooks []ContainerHooks
+
+	for _, containerField := range []string{"initContainers", "containers"} {
+		containers, found, _ := unstructured.NestedSlice(item.Object, append(specPath, containerField)...)
+		if !found {
+			continue
+		}
+
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			containerName, _ := containerMap["name"].(string)
+			containerHooks := ContainerHooks{Name: containerName, Init: containerField == "initContainers"}
+			containerHooks.PostStart, _, _ = unstructured.NestedMap(containerMap, "lifecycle", "postStart")
+			containerHooks.PreStop, _, _ = unstructured.NestedMap(containerMap, "lifecycle", "preStop")
+			hooks = append(hooks, containerHooks)
+		}
+	}
+
+	return hooks
+}
+
+// extractVolumes extracts the volumes of the pod spec with their source type and what the source refers to
+func extractVolumes(item unstructured.Unstructured) []VolumeInfo {
+	volumes, found, _ := unstructured.NestedSlice(item.Object, append(getPodSpecPath(item), "volumes")...)
+	if !found {
+		return nil
+	}
+
+	var result []VolumeInfo
+	for _, volume := range volumes {
+		volumeMap, ok := volume.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		info := VolumeInfo{}
+		info.Name, _ = volumeMap["name"].(string)
+		for key, value := range volumeMap {
+			if key == "name" {
+				continue
+			}
+			info.Type = key
+			source, _ := value.(map[string]interface{})
+			info.Source = volumeSourceReference(key, source)
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// volumeSourceReference returns what a volume source points to: the claim, ConfigMap or Secret name,
+// the host or NFS path or the CSI driver. Empty for sources that don't refer to anything (emptyDir, downwardAPI, ...)
+func volumeSourceReference(sourceType string, source map[string]interface{}) string {
+	field := map[string]string{
+		"persistentVolumeClaim": "claimName",
+		"configMap":             "name",
+		"secret":                "secretName",
+		"hostPath":              "path",
+		"csi":                   "driver",
+	}[sourceType]
+	if sourceType == "nfs" {
+		server, _ := source["server"].(string)
+		path, _ := source["path"].(string)
+		return server + ":" + path
+	}
+	reference, _ := source[field].(string)
+	return reference
+}
+
+// extractVolumeClaimTemplates extracts spec.volumeClaimTemplates of a StatefulSet
+// They live next to the pod template rather than in the pod spec, so other kinds have none
+func extractVolumeClaimTemplates(item unstructured.Unstructured) []VolumeClaimTemplate {
+	if item.GetKind() != "StatefulSet" {
+		return nil
+	}
+	templates, found, _ := unstructured.NestedSlice(item.Object, "spec", "volumeClaimTemplates")
+	if !found {
+		return nil
+	}
+
+	var result []VolumeClaimTemplate
+	for _, template := range templates {
+		templateMap, ok := template.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		claim := VolumeClaimTemplate{}
+		claim.Name, _, _ = unstructured.NestedString(templateMap, "metadata", "name")
+		claim.StorageClassName, _, _ = unstructured.NestedString(templateMap, "spec", "storageClassName")
+		claim.AccessModes, _, _ = unstructured.NestedStringSlice(templateMap, "spec", "accessModes")
+		if storage, found, _ := unstructured.NestedFieldNoCopy(templateMap, "spec", "resources", "requests", "storage"); found {
+			claim.Storage = fmt.Sprintf("%v", storage)
+		}
+		result = append(result, claim)
+	}
+	return result
+}
+
+// extractReadinessGates extracts spec.readinessGates and, for Pods, the status.conditions they wait for
+// Controllers such as load balancers or service meshes set these conditions, a pod stays NotReady until all are True
+func extractReadinessGates(item unstructured.Unstructured) []ReadinessGate {
+	gates, found, _ := unstructured.NestedSlice(item.Object, append(getPodSpecPath(item), "readinessGates")...)
+	if !found {
+		return nil
+	}
+
+	// Index the pod conditions by type, templates have none
+	isPod := item.GetKind() == "Pod"
+	conditions := make(map[string]map[string]interface{})
+	if isPod {
+		statusConditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+		for _, condition := range statusConditions {
+			if conditionMap, ok := condition.(map[string]interface{}); ok {
+				if conditionType, ok := conditionMap["type"].(string); ok {
+					conditions[conditionType] = conditionMap
+				}
+			}
+		}
+	}
+
+	var result []ReadinessGate
+	for _, gate := range gates {
+		gateMap, ok := gate.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		readinessGate := ReadinessGate{}
+		readinessGate.ConditionType, _ = gateMap["conditionType"].(string)
+		if isPod {
+			readinessGate.Status = "Missing"
+			if condition, ok := conditions[readinessGate.ConditionType]; ok {
+				readinessGate.Status, _ = condition["status"].(string)
+				readinessGate.Reason, _ = condition["reason"].(string)
+				readinessGate.Message, _ = condition["message"].(string)
+			}
+		}
+		result = append(result, readinessGate)
+	}
+	return result
+}
+
+// Annotations written by the deployment controller and by "kubectl annotate"/"--record"
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// extractAnnotationValues returns the values of the given annotation keys, skipping the missing ones
+func extractAnnotationValues(item unstructured.Unstructured, keys ...string) map[string]string {
+	annotations := item.GetAnnotations()
+	values := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := annotations[key]; ok {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// extractRevisionInfo extracts the rollout revision and change cause annotations
+// Returns nil when the resource has neither (e.g. it was never rolled out by a Deployment)
+func extractRevisionInfo(item unstructured.Unstructured) *RevisionInfo {
+	values := extractAnnotationValues(item, revisionAnnotation, changeCauseAnnotation)
+	if len(values) == 0 {
+		return nil
+	}
+
+	return &RevisionInfo{
+		Revision:    values[revisionAnnotation],
+		ChangeCause: values[changeCauseAnnotation],
+	}
+}
+
+// managedFieldsDepth is how deep field paths are reported by summarizeManagedFields
+const managedFieldsDepth = 2
+
+// summarizeManagedFields reports which field manager owns which fields, from metadata.managedFields
+// Paths stop at managedFieldsDepth levels so the summary stays readable (spec.template, not every container field)
+func summarizeManagedFields(item unstructured.Unstructured) []ManagedFieldsSummary {
+	var summaries []ManagedFieldsSummary
+	for _, entry := range item.GetManagedFields() {
+		summary := ManagedFieldsSummary{
+			Manager:     entry.Manager,
+			Operation:   string(entry.Operation),
+			Subresource: entry.Subresource,
+		}
+
+		if entry.FieldsV1 != nil {
+			var fieldSet map[string]interface{}
+			if err := json.Unmarshal(entry.FieldsV1.Raw, &fieldSet); err == nil {
+				summary.Fields = collectFieldPaths(fieldSet, "", managedFieldsDepth)
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// collectFieldPaths walks a FieldsV1 set and returns the sorted dotted paths of its "f:" fields down to depth
+// List items ("k:", "v:", "i:") are not expanded, their parent field is reported instead
+func collectFieldPaths(fieldSet map[string]interface{}, prefix string, depth int) []string {
+	var paths []string
+	for key, value := range fieldSet {
+		if !strings.HasPrefix(key, "f:") {
+			continue
+		}
+		path := strings.TrimPrefix(key, "f:")
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		children, ok := value.(map[string]interface{})
+		if depth > 1 && ok && hasFieldChildren(children) {
+			paths = append(paths, collectFieldPaths(children, path, depth-1)...)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// hasFieldChildren checks if a FieldsV1 set has nested "f:" fields
+func hasFieldChildren(fieldSet map[string]interface{}) bool {
+	for key := range fieldSet {
+		if strings.HasPrefix(key, "f:") {
+			return true
+		}
+	}
+	return false
+}
+
+// extractIdentityInfo extracts serviceAccountName, automountServiceAccountToken and imagePullSecrets
+func extractIdentityInfo(item unstructured.Unstructured) *IdentityInfo {
+	specPath := getPodSpecPath(item)
+	info := &IdentityInfo{ServiceAccountName: "default"}
+
+	if serviceAccount, found, _ := unstructured.NestedString(item.Object, append(specPath, "serviceAccountName")...); found && serviceAccount != "" {
+		info.ServiceAccountName = serviceAccount
+	}
+	if automount, found, _ := unstructured.NestedBool(item.Object, append(specPath, "automountServiceAccountToken")...); found {
+		info.AutomountServiceAccountToken = &automount
+	}
+
+	pullSecrets, _, _ := unstructured.NestedSlice(item.Object, append(specPath, "imagePullSecrets")...)
+	for _, secret := range pullSecrets {
+		if secretMap, ok := secret.(map[string]interface{}); ok {
+			if name, ok := secretMap["name"].(string); ok {
+				info.ImagePullSecrets = append(info.ImagePullSecrets, name)
+			}
+		}
+	}
+
+	return info
+}
+
+// extractNetworkInfo extracts dnsPolicy, dnsConfig and hostAliases from the pod spec
+// Returns nil for kinds without a pod spec
+func extractNetworkInfo(item unstructured.Unstructured) *NetworkInfo {
+	if !hasPodSpec(item) {
+		return nil
+	}
+	specPath := getPodSpecPath(item)
+	info := &NetworkInfo{DNSPolicy: "ClusterFirst"}
+
+	if dnsPolicy, found, _ := unstructured.NestedString(item.Object, append(specPath, "dnsPolicy")...); found && dnsPolicy != "" {
+		info.DNSPolicy = dnsPolicy
+	}
+	info.DNSConfig, _, _ = unstructured.NestedMap(item.Object, append(specPath, "dnsConfig")...)
+
+	hostAliases, _, _ := unstructured.NestedSlice(item.Object, append(specPath, "hostAliases")...)
+	for _, alias := range hostAliases {
+		if aliasMap, ok := alias.(map[string]interface{}); ok {
+			hostAlias := HostAlias{}
+			hostAlias.IP, _, _ = unstructured.NestedString(aliasMap, "ip")
+			hostAlias.Hostnames, _, _ = unstructured.NestedStringSlice(aliasMap, "hostnames")
+			info.HostAliases = append(info.HostAliases, hostAlias)
+		}
+	}
+
+	return info
+}
+
+// extractReplicasInfo extracts the desired replicas from spec and the observed ones from status
+// DaemonSets have no spec.replicas and report scheduled/ready pods instead
+// Returns nil for kinds that aren't scaled by replicas
+func extractReplicasInfo(item unstructured.Unstructured) *ReplicasInfo {
+	nestedCount := func(fields ...string) int64 {
+		count, _, _ := unstructured.NestedInt64(item.Object, fields...)
+		return count
+	}
+
+	switch item.GetKind() {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		// spec.replicas defaults to 1 when unset
+		desired, found, _ := unstructured.NestedInt64(item.Object, "spec", "replicas")
+		if !found {
+			desired = 1
+		}
+		return &ReplicasInfo{
+			Desired:   desired,
+			Current:   nestedCount("status", "replicas"),
+			Ready:     nestedCount("status", "readyReplicas"),
+			Available: nestedCount("status", "availableReplicas"),
+		}
+	case "DaemonSet":
+		return &ReplicasInfo{
+			Desired:   nestedCount("status", "desiredNumberScheduled"),
+			Current:   nestedCount("status", "currentNumberScheduled"),
+			Ready:     nestedCount("status", "numberReady"),
+			Available: nestedCount("status", "numberAvailable"),
+		}
+	}
+
+	return nil
+}
+
+// extractFinalizersInfo extracts metadata.finalizers and metadata.deletionTimestamp
+func extractFinalizersInfo(item unstructured.Unstructured) *FinalizersInfo {
+	info := &FinalizersInfo{Finalizers: item.GetFinalizers()}
+	if deletionTimestamp := item.GetDeletionTimestamp(); deletionTimestamp != nil {
+		info.DeletionTimestamp = deletionTimestamp.UTC().Format(time.RFC3339)
+		info.Stuck = len(info.Finalizers) > 0
+	}
+	return info
+}
+
+// extractServiceInfo extracts type, clusterIP(s), selector and externalTrafficPolicy of a Service
+// Returns nil for other kinds, they have no such fields
+func extractServiceInfo(item unstructured.Unstructured) *ServiceInfo {
+	if item.GetKind() != "Service" {
+		return nil
+	}
+
+	info := &ServiceInfo{Type: "ClusterIP"}
+	if serviceType, found, _ := unstructured.NestedString(item.Object, "spec", "type"); found && serviceType != "" {
+		info.Type = serviceType
+	}
+	info.ClusterIP, _, _ = unstructured.NestedString(item.Object, "spec", "clusterIP")
+	info.ClusterIPs, _, _ = unstructured.NestedStringSlice(item.Object, "spec", "clusterIPs")
+	info.Selector, _, _ = unstructured.NestedStringMap(item.Object, "spec", "selector")
+	info.ExternalTrafficPolicy, _, _ = unstructured.NestedString(item.Object, "spec", "externalTrafficPolicy")
+
+	return info
+}
+
+// extractLifecycleInfo extracts restartPolicy, terminationGracePeriodSeconds and activeDeadlineSeconds
+func extractLifecycleInfo(item unstructured.Unstructured) *LifecycleInfo {
+	specPath := getPodSpecPath(item)
+	info := &LifecycleInfo{}
+
+	info.RestartPolicy, _, _ = unstructured.NestedString(item.Object, append(specPath, "restartPolicy")...)
+	if gracePeriod, found, _ := unstructured.NestedInt64(item.Object, append(specPath, "terminationGracePeriodSeconds")...); found {
+		info.TerminationGracePeriodSeconds = &gracePeriod
+	}
+
+	// Jobs have their own deadline, which bounds all of their pods
+	deadlinePath := append(specPath, "activeDeadlineSeconds")
+	switch item.GetKind() {
+	case "Job":
+		deadlinePath = []string{"spec", "activeDeadlineSeconds"}
+	case "CronJob":
+		deadlinePath = []string{"spec", "jobTemplate", "spec", "activeDeadlineSeconds"}
+	}
+	if deadline, found, _ := unstructured.NestedInt64(item.Object, deadlinePath...); found {
+		info.ActiveDeadlineSeconds = &deadline
+	}
+
+	return info
+}
+
+// toStringSlice converts an unstructured []interface{} of strings to a []string
+func toStringSlice(value interface{}) []string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// isStaticPod tells whether a pod is the mirror pod of a static pod, created by the kubelet from a manifest on the node
+// The kubelet marks mirror pods with the kubernetes.io/config.mirror annotation and makes their Node the owner
+func isStaticPod(item unstructured.Unstructured) bool {
+	if item.GetKind() != "Pod" {
+		return false
+	}
+	if _, ok := item.GetAnnotations()["kubernetes.io/config.mirror"]; ok {
+		return true
+	}
+	for _, ownerRef := range item.GetOwnerReferences() {
+		if ownerRef.Kind == "Node" && ownerRef.APIVersion == "v1" {
+			return true
+		}
+	}
+	return false
+}
+
 // extractOwnerReferences extracts owner references from a resource
 func extractOwnerReferences(item unstructured.Unstructured) []OwnerReference {
 	ownerRefs := []OwnerReference{}
@@ -48,6 +755,11 @@ func extractOwnerReferences(item unstructured.Unstructured) []OwnerReference {
 
 		ownerRef := OwnerReference{}
 
+		// Extract apiVersion (e.g., apps/v1)
+		if apiVersion, ok := refMap["apiVersion"].(string); ok {
+			ownerRef.APIVersion = apiVersion
+		}
+
 		// Extract kind
 		if kind, ok := refMap["kind"].(string); ok {
 			ownerRef.Kind = kind
@@ -58,6 +770,14 @@ func extractOwnerReferences(item unstructured.Unstructured) []OwnerReference {
 			ownerRef.Name = name
 		}
 
+		// Extract uid and controller, to tell apart an owner recreated with the same name
+		if uid, ok := refMap["uid"].(string); ok {
+			ownerRef.UID = uid
+		}
+		if controller, ok := refMap["controller"].(bool); ok {
+			ownerRef.Controller = controller
+		}
+
 		// Extract namespace (may not be present in all cases)
 		// If ownerReference doesn't have namespace, use the namespace of the current object
 		if namespace, ok := refMap["namespace"].(string); ok && namespace != "" {
@@ -73,6 +793,16 @@ func extractOwnerReferences(item unstructured.Unstructured) []OwnerReference {
 	return ownerRefs
 }
 
+// controllerOwner returns the first owner reference marked as controller, nil when no owner manages the resource
+func controllerOwner(ownerRefs []OwnerReference) *OwnerReference {
+	for i := range ownerRefs {
+		if ownerRefs[i].Controller {
+			return &ownerRefs[i]
+		}
+	}
+	return nil
+}
+
 // extractSchedulingInfo extracts all scheduling-related information from a resource
 func extractSchedulingInfo(item unstructured.Unstructured) *SchedulingInfo {
 	specPath := getPodSpecPath(item)
@@ -116,32 +846,10 @@ func extractSchedulingInfo(item unstructured.Unstructured) *SchedulingInfo {
 
 			if resources, ok := containerMap["resources"].(map[string]interface{}); ok {
 				if req, ok := resources["requests"].(map[string]interface{}); ok {
-					for k, v := range req {
-						if existing, exists := requests[k]; exists {
-							// Sum resources if multiple containers
-							if existingStr, ok := existing.(string); ok {
-								if vStr, ok := v.(string); ok {
-									// Simple string comparison for now
-									requests[k] = existingStr + "," + vStr
-								}
-							}
-						} else {
-							requests[k] = v
-						}
-					}
+					addContainerQuantities(requests, req)
 				}
 				if lim, ok := resources["limits"].(map[string]interface{}); ok {
-					for k, v := range lim {
-						if existing, exists := limits[k]; exists {
-							if existingStr, ok := existing.(string); ok {
-								if vStr, ok := v.(string); ok {
-									limits[k] = existingStr + "," + vStr
-								}
-							}
-						} else {
-							limits[k] = v
-						}
-					}
+					addContainerQuantities(limits, lim)
 				}
 			}
 		}
@@ -154,6 +862,11 @@ func extractSchedulingInfo(item unstructured.Unstructured) *SchedulingInfo {
 		}
 	}
 
+	// Overhead
+	if overhead, found, _ := unstructured.NestedMap(item.Object, append(specPath, "overhead")...); found && len(overhead) > 0 {
+		scheduling.Overhead = overhead
+	}
+
 	// SchedulerName
 	if schedulerName, found, _ := unstructured.NestedString(item.Object, append(specPath, "schedulerName")...); found && schedulerName != "" {
 		scheduling.SchedulerName = schedulerName
@@ -198,7 +911,7 @@ func extractSchedulingInfo(item unstructured.Unstructured) *SchedulingInfo {
 	// Return nil if no scheduling info found
 	if scheduling.NodeSelector == nil && scheduling.NodeName == "" && scheduling.Affinity == nil &&
 		len(scheduling.Tolerations) == 0 && len(scheduling.TopologySpreadConstraints) == 0 &&
-		scheduling.ResourceRequests == nil && scheduling.ResourceLimits == nil &&
+		scheduling.ResourceRequests == nil && scheduling.ResourceLimits == nil && scheduling.Overhead == nil &&
 		scheduling.SchedulerName == "" && scheduling.PriorityClassName == "" && scheduling.Priority == nil &&
 		scheduling.PreemptionPolicy == "" && scheduling.RuntimeClassName == "" &&
 		!scheduling.HostNetwork && !scheduling.HostPID && !scheduling.HostIPC {
@@ -208,6 +921,46 @@ func extractSchedulingInfo(item unstructured.Unstructured) *SchedulingInfo {
 	return scheduling
 }
 
+// compactAffinity prunes empty nodeAffinity/podAffinity/podAntiAffinity branches and empty
+// arrays from an affinity map, so only populated rules remain. Returns nil if nothing is left.
+func compactAffinity(affinity map[string]interface{}) map[string]interface{} {
+	compacted, ok := pruneEmpty(affinity).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return compacted
+}
+
+// pruneEmpty recursively removes nil values, empty maps and empty slices from a value
+func pruneEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pruned := make(map[string]interface{})
+		for key, child := range v {
+			if prunedChild := pruneEmpty(child); prunedChild != nil {
+				pruned[key] = prunedChild
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+	case []interface{}:
+		var pruned []interface{}
+		for _, child := range v {
+			if prunedChild := pruneEmpty(child); prunedChild != nil {
+				pruned = append(pruned, prunedChild)
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+	default:
+		return v
+	}
+}
+
 // extractSchedulingSubcommand extracts a specific scheduling field based on subcommand
 func extractSchedulingSubcommand(item unstructured.Unstructured, outputItem *OutputItem, subCommand string) {
 	specPath := getPodSpecPath(item)
@@ -246,10 +999,13 @@ func extractSchedulingSubcommand(item unstructured.Unstructured, outputItem *Out
 					if lim, ok := res["limits"].(map[string]interface{}); ok && len(lim) > 0 {
 						cr.Limits = lim
 					}
+					if claims, ok := res["claims"].([]interface{}); ok && len(claims) > 0 {
+						cr.Claims = claims
+					}
 				}
 
 				// Only add container if it has any resources defined
-				if cr.Requests != nil || cr.Limits != nil {
+				if cr.Requests != nil || cr.Limits != nil || cr.Claims != nil {
 					containerResources = append(containerResources, cr)
 				}
 			}
@@ -257,6 +1013,14 @@ func extractSchedulingSubcommand(item unstructured.Unstructured, outputItem *Out
 		if len(containerResources) > 0 {
 			outputItem.Resources = containerResources
 		}
+		// The overhead of the RuntimeClass counts toward the pod's requests, it isn't part of any container
+		if overhead, found, _ := unstructured.NestedMap(item.Object, append(specPath, "overhead")...); found && len(overhead) > 0 {
+			outputItem.Overhead = overhead
+		}
+		// Claims for dynamic resource allocation (GPUs, etc.), the containers refer to them by name
+		if claims, found, _ := unstructured.NestedSlice(item.Object, append(specPath, "resourceClaims")...); found && len(claims) > 0 {
+			outputItem.ResourceClaims = claims
+		}
 	case "topology":
 		if topology, found, _ := unstructured.NestedSlice(item.Object, append(specPath, "topologySpreadConstraints")...); found && len(topology) > 0 {
 			outputItem.TopologySpreadConstraints = topology
@@ -295,3 +1059,25 @@ func extractSchedulingSubcommand(item unstructured.Unstructured, outputItem *Out
 	}
 }
 
+// addContainerQuantities adds one container's requests or limits to the pod totals
+// Values of a key already present are summed as quantities (250m + 250m = 500m). A value that does
+// not parse is kept as-is when the key is new, so nothing is silently dropped.
+func addContainerQuantities(totals, quantities map[string]interface{}) {
+	for k, v := range quantities {
+		existing, exists := totals[k]
+		if !exists {
+			totals[k] = v
+			continue
+		}
+		sum, err := resource.ParseQuantity(fmt.Sprint(existing))
+		if err != nil {
+			continue
+		}
+		q, err := resource.ParseQuantity(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+		sum.Add(q)
+		totals[k] = sum.String()
+	}
+}
@@ -0,0 +1,606 @@
+package main
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// newTestWorkload returns an unstructured resource of the given kind with podSpec placed where that kind keeps it
+func newTestWorkload(apiVersion, kind, name string, podSpec map[string]interface{}) unstructured.Unstructured {
+	item := unstructured.Unstructured{Object: map[string]interface{}{}}
+	item.SetAPIVersion(apiVersion)
+	item.SetKind(kind)
+	item.SetNamespace("default")
+	item.SetName(name)
+	if err := unstructured.SetNestedField(item.Object, podSpec, getPodSpecPath(item)...); err != nil {
+		panic(err)
+	}
+	return item
+}
+
+// testSchedulingPodSpec returns a pod spec with a node selector, a toleration and container resources
+func testSchedulingPodSpec() map[string]interface{} {
+	return map[string]interface{}{
+		"nodeSelector": map[string]interface{}{"kubernetes.io/arch": "amd64"},
+		"tolerations": []interface{}{
+			map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "batch", "effect": "NoSchedule"},
+		},
+		"priorityClassName": "high",
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name": "app",
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"cpu": "100m"},
+					"limits":   map[string]interface{}{"memory": "128Mi"},
+				},
+			},
+		},
+	}
+}
+
+func TestGetPodSpecPath(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		kind       string
+		want       []string
+	}{
+		{"v1", "Pod", []string{"spec"}},
+		{"apps/v1", "Deployment", []string{"spec", "template", "spec"}},
+		{"apps/v1", "StatefulSet", []string{"spec", "template", "spec"}},
+		{"batch/v1", "Job", []string{"spec", "template", "spec"}},
+		{"batch/v1", "CronJob", []string{"spec", "jobTemplate", "spec", "template", "spec"}},
+		{"v1", "Service", []string{"spec"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			item := unstructured.Unstructured{Object: map[string]interface{}{}}
+			item.SetAPIVersion(tt.apiVersion)
+			item.SetKind(tt.kind)
+			if got := getPodSpecPath(item); !equalStrings(got, tt.want) {
+				t.Errorf("getPodSpecPath(%s) = %v, want %v", tt.kind, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractSchedulingInfo(t *testing.T) {
+	kinds := []struct {
+		apiVersion string
+		kind       string
+	}{
+		{"v1", "Pod"},
+		{"apps/v1", "Deployment"},
+		{"batch/v1", "CronJob"},
+	}
+
+	for _, k := range kinds {
+		t.Run(k.kind, func(t *testing.T) {
+			scheduling := extractSchedulingInfo(newTestWorkload(k.apiVersion, k.kind, "web", testSchedulingPodSpec()))
+			if scheduling == nil {
+				t.Fatal("extractSchedulingInfo() = nil, want scheduling info")
+			}
+			if got := scheduling.NodeSelector["kubernetes.io/arch"]; got != "amd64" {
+				t.Errorf("NodeSelector[kubernetes.io/arch] = %q, want amd64", got)
+			}
+			if len(scheduling.Tolerations) != 1 {
+				t.Errorf("len(Tolerations) = %d, want 1", len(scheduling.Tolerations))
+			}
+			if scheduling.PriorityClassName != "high" {
+				t.Errorf("PriorityClassName = %q, want high", scheduling.PriorityClassName)
+			}
+			if got := scheduling.ResourceRequests["cpu"]; got != "100m" {
+				t.Errorf("ResourceRequests[cpu] = %v, want 100m", got)
+			}
+			if got := scheduling.ResourceLimits["memory"]; got != "128Mi" {
+				t.Errorf("ResourceLimits[memory] = %v, want 128Mi", got)
+			}
+		})
+	}
+}
+
+func TestExtractSchedulingInfoEmpty(t *testing.T) {
+	item := newTestWorkload("apps/v1", "Deployment", "web", map[string]interface{}{
+		"containers": []interface{}{map[string]interface{}{"name": "app"}},
+	})
+	if scheduling := extractSchedulingInfo(item); scheduling != nil {
+		t.Errorf("extractSchedulingInfo() = %+v, want nil", scheduling)
+	}
+}
+
+func TestExtractSchedulingInfoMergesContainerResources(t *testing.T) {
+	item := newTestWorkload("v1", "Pod", "web", map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":      "app",
+				"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "250m"}},
+			},
+			map[string]interface{}{
+				"name":      "sidecar",
+				"resources": map[string]interface{}{"requests": map[string]interface{}{"memory": "64Mi", "cpu": "100m"}},
+			},
+			map[string]interface{}{
+				"name":      "proxy",
+				"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "650m", "memory": "64Mi"}},
+			},
+		},
+	})
+
+	scheduling := extractSchedulingInfo(item)
+	if scheduling == nil {
+		t.Fatal("extractSchedulingInfo() = nil, want resource requests")
+	}
+	want := map[string]interface{}{"cpu": "1", "memory": "128Mi"}
+	if !reflect.DeepEqual(scheduling.ResourceRequests, want) {
+		t.Errorf("ResourceRequests = %v, want %v", scheduling.ResourceRequests, want)
+	}
+	if scheduling.ResourceLimits != nil {
+		t.Errorf("ResourceLimits = %v, want nil", scheduling.ResourceLimits)
+	}
+}
+
+func TestExtractOwnerReferences(t *testing.T) {
+	pod := newTestPod("default", "web-7d9f8-abcde")
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-7d9f8", "uid": "0f3c2a9e", "controller": true},
+		map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "name": "settings", "namespace": "config"},
+	}, "metadata", "ownerReferences")
+
+	want := []OwnerReference{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f8", Namespace: "default", UID: "0f3c2a9e", Controller: true},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "settings", Namespace: "config"},
+	}
+	if got := extractOwnerReferences(*pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractOwnerReferences() = %+v, want %+v", got, want)
+	}
+
+	if got := extractOwnerReferences(*newTestPod("default", "orphan")); len(got) != 0 {
+		t.Errorf("extractOwnerReferences() without owners = %+v, want empty", got)
+	}
+}
+
+func TestOwnerExtractorFlattensController(t *testing.T) {
+	pod := newTestPod("default", "web-7d9f8-abcde")
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "name": "settings"},
+		map[string]interface{}{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-7d9f8", "controller": true},
+	}, "metadata", "ownerReferences")
+
+	var item OutputItem
+	commandExtractors["owner"](*pod, &item)
+	if item.OwnerKind != "ReplicaSet" || item.OwnerName != "web-7d9f8" {
+		t.Errorf("owner extractor = %s/%s, want the controller ReplicaSet/web-7d9f8", item.OwnerKind, item.OwnerName)
+	}
+
+	// Owners that aren't controllers don't fill the flat fields
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "name": "settings"},
+	}, "metadata", "ownerReferences")
+	item = OutputItem{}
+	commandExtractors["owner"](*pod, &item)
+	if item.OwnerKind != "" || item.OwnerName != "" {
+		t.Errorf("owner extractor without controller = %s/%s, want empty", item.OwnerKind, item.OwnerName)
+	}
+}
+
+func TestIsStaticPod(t *testing.T) {
+	mirror := newTestPod("kube-system", "etcd-control-plane")
+	mirror.SetAnnotations(map[string]string{"kubernetes.io/config.mirror": "3c2f4a"})
+
+	nodeOwned := newTestPod("kube-system", "kube-proxy-node1")
+	_ = unstructured.SetNestedSlice(nodeOwned.Object, []interface{}{
+		map[string]interface{}{"apiVersion": "v1", "kind": "Node", "name": "node1", "uid": "9a1b"},
+	}, "metadata", "ownerReferences")
+
+	deployment := newTestWorkload("apps/v1", "Deployment", "web", map[string]interface{}{})
+	deployment.SetAnnotations(map[string]string{"kubernetes.io/config.mirror": "3c2f4a"})
+
+	tests := []struct {
+		name string
+		item unstructured.Unstructured
+		want bool
+	}{
+		{name: "mirror annotation", item: *mirror, want: true},
+		{name: "owned by its node", item: *nodeOwned, want: true},
+		{name: "regular pod", item: *newTestPod("default", "web"), want: false},
+		{name: "not a pod", item: deployment, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStaticPod(tt.item); got != tt.want {
+				t.Errorf("isStaticPod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPodLabelsCronJob(t *testing.T) {
+	item := newTestWorkload("batch/v1", "CronJob", "backup", map[string]interface{}{})
+	_ = unstructured.SetNestedStringMap(item.Object, map[string]string{"app": "backup"},
+		"spec", "jobTemplate", "spec", "template", "metadata", "labels")
+
+	if got := getPodLabels(item)["app"]; got != "backup" {
+		t.Errorf("getPodLabels()[app] = %q, want backup", got)
+	}
+}
+
+func TestExtractNetworkInfo(t *testing.T) {
+	item := newTestWorkload("apps/v1", "Deployment", "web", map[string]interface{}{
+		"dnsConfig": map[string]interface{}{"nameservers": []interface{}{"1.1.1.1"}},
+		"hostAliases": []interface{}{
+			map[string]interface{}{"ip": "10.0.0.5", "hostnames": []interface{}{"db", "db.internal"}},
+		},
+	})
+
+	network := extractNetworkInfo(item)
+	if network == nil {
+		t.Fatal("extractNetworkInfo() = nil, want network info")
+	}
+	if network.DNSPolicy != "ClusterFirst" {
+		t.Errorf("DNSPolicy = %q, want ClusterFirst", network.DNSPolicy)
+	}
+	wantAliases := []HostAlias{{IP: "10.0.0.5", Hostnames: []string{"db", "db.internal"}}}
+	if !reflect.DeepEqual(network.HostAliases, wantAliases) {
+		t.Errorf("HostAliases = %+v, want %+v", network.HostAliases, wantAliases)
+	}
+	if network.DNSConfig == nil {
+		t.Error("DNSConfig = nil, want the pod's dnsConfig")
+	}
+
+	configMap := unstructured.Unstructured{Object: map[string]interface{}{}}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	if got := extractNetworkInfo(configMap); got != nil {
+		t.Errorf("extractNetworkInfo(ConfigMap) = %+v, want nil", got)
+	}
+}
+
+func TestExtractReplicasInfo(t *testing.T) {
+	newObject := func(kind string, fields map[string]interface{}) unstructured.Unstructured {
+		item := unstructured.Unstructured{Object: fields}
+		item.SetAPIVersion("apps/v1")
+		item.SetKind(kind)
+		item.SetName("web")
+		return item
+	}
+
+	tests := []struct {
+		name string
+		item unstructured.Unstructured
+		want *ReplicasInfo
+	}{
+		{
+			name: "deployment rolling out",
+			item: newObject("Deployment", map[string]interface{}{
+				"spec":   map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{"replicas": int64(4), "readyReplicas": int64(2), "availableReplicas": int64(2)},
+			}),
+			want: &ReplicasInfo{Desired: 3, Current: 4, Ready: 2, Available: 2},
+		},
+		{
+			name: "statefulset without spec.replicas defaults to one",
+			item: newObject("StatefulSet", map[string]interface{}{
+				"status": map[string]interface{}{"replicas": int64(1), "readyReplicas": int64(1)},
+			}),
+			want: &ReplicasInfo{Desired: 1, Current: 1, Ready: 1},
+		},
+		{
+			name: "daemonset counts scheduled pods",
+			item: newObject("DaemonSet", map[string]interface{}{
+				"status": map[string]interface{}{"desiredNumberScheduled": int64(5), "currentNumberScheduled": int64(5), "numberReady": int64(4), "numberAvailable": int64(4)},
+			}),
+			want: &ReplicasInfo{Desired: 5, Current: 5, Ready: 4, Available: 4},
+		},
+		{
+			name: "pod has no replicas",
+			item: *newTestPod("default", "web"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractReplicasInfo(tt.item); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractReplicasInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractServiceInfo(t *testing.T) {
+	service := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"type":                  "LoadBalancer",
+			"clusterIP":             "10.96.0.10",
+			"clusterIPs":            []interface{}{"10.96.0.10", "fd00::10"},
+			"selector":              map[string]interface{}{"app": "web"},
+			"externalTrafficPolicy": "Local",
+		},
+	}}
+	service.SetAPIVersion("v1")
+	service.SetKind("Service")
+
+	want := &ServiceInfo{
+		Type:                  "LoadBalancer",
+		ClusterIP:             "10.96.0.10",
+		ClusterIPs:            []string{"10.96.0.10", "fd00::10"},
+		Selector:              map[string]string{"app": "web"},
+		ExternalTrafficPolicy: "Local",
+	}
+	if got := extractServiceInfo(service); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractServiceInfo() = %+v, want %+v", got, want)
+	}
+
+	// spec.type defaults to ClusterIP
+	headless := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"clusterIP": "None"},
+	}}
+	headless.SetKind("Service")
+	if got, want := extractServiceInfo(headless), (&ServiceInfo{Type: "ClusterIP", ClusterIP: "None"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractServiceInfo(headless) = %+v, want %+v", got, want)
+	}
+
+	if got := extractServiceInfo(*newTestPod("default", "web")); got != nil {
+		t.Errorf("extractServiceInfo(Pod) = %+v, want nil", got)
+	}
+}
+
+func TestExtractVolumes(t *testing.T) {
+	item := newTestWorkload("apps/v1", "StatefulSet", "db", map[string]interface{}{
+		"volumes": []interface{}{
+			map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "db-config"}},
+			map[string]interface{}{"name": "backup", "nfs": map[string]interface{}{"server": "nas", "path": "/exports/db"}},
+			map[string]interface{}{"name": "scratch", "emptyDir": map[string]interface{}{}},
+		},
+	})
+	_ = unstructured.SetNestedSlice(item.Object, []interface{}{
+		map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "data"},
+			"spec": map[string]interface{}{
+				"accessModes": []interface{}{"ReadWriteOnce"},
+				"resources":   map[string]interface{}{"requests": map[string]interface{}{"storage": "10Gi"}},
+			},
+		},
+	}, "spec", "volumeClaimTemplates")
+
+	wantVolumes := []VolumeInfo{
+		{Name: "config", Type: "configMap", Source: "db-config"},
+		{Name: "backup", Type: "nfs", Source: "nas:/exports/db"},
+		{Name: "scratch", Type: "emptyDir"},
+	}
+	if got := extractVolumes(item); !reflect.DeepEqual(got, wantVolumes) {
+		t.Errorf("extractVolumes() = %+v, want %+v", got, wantVolumes)
+	}
+
+	wantTemplates := []VolumeClaimTemplate{{Name: "data", Storage: "10Gi", AccessModes: []string{"ReadWriteOnce"}}}
+	if got := extractVolumeClaimTemplates(item); !reflect.DeepEqual(got, wantTemplates) {
+		t.Errorf("extractVolumeClaimTemplates() = %+v, want %+v", got, wantTemplates)
+	}
+	if got := formatVolumeClaimTemplate(wantTemplates[0]); got != "10Gi ReadWriteOnce storageClass=<default>" {
+		t.Errorf("formatVolumeClaimTemplate() = %q", got)
+	}
+
+	deployment := newTestWorkload("apps/v1", "Deployment", "web", map[string]interface{}{})
+	deployment.Object["spec"].(map[string]interface{})["volumeClaimTemplates"] = []interface{}{}
+	if got := extractVolumeClaimTemplates(deployment); got != nil {
+		t.Errorf("extractVolumeClaimTemplates() for a Deployment = %+v, want nil", got)
+	}
+}
+
+func TestExtractReadinessGates(t *testing.T) {
+	pod := newTestPod("default", "web")
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"conditionType": "target-health.elbv2.k8s.aws/web"},
+		map[string]interface{}{"conditionType": "example.com/mesh-ready"},
+	}, "spec", "readinessGates")
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "False"},
+		map[string]interface{}{"type": "target-health.elbv2.k8s.aws/web", "status": "False", "reason": "Elb.RegistrationInProgress"},
+	}, "status", "conditions")
+
+	want := []ReadinessGate{
+		{ConditionType: "target-health.elbv2.k8s.aws/web", Status: "False", Reason: "Elb.RegistrationInProgress"},
+		{ConditionType: "example.com/mesh-ready", Status: "Missing"},
+	}
+	if got := extractReadinessGates(*pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractReadinessGates() = %+v, want %+v", got, want)
+	}
+
+	// Pod templates have gates but no conditions
+	deployment := newTestWorkload("apps/v1", "Deployment", "web", map[string]interface{}{
+		"readinessGates": []interface{}{map[string]interface{}{"conditionType": "example.com/mesh-ready"}},
+	})
+	wantTemplate := []ReadinessGate{{ConditionType: "example.com/mesh-ready"}}
+	if got := extractReadinessGates(deployment); !reflect.DeepEqual(got, wantTemplate) {
+		t.Errorf("extractReadinessGates() for a Deployment = %+v, want %+v", got, wantTemplate)
+	}
+
+	if got := extractReadinessGates(*newTestPod("default", "api")); got != nil {
+		t.Errorf("extractReadinessGates() without gates = %+v, want nil", got)
+	}
+}
+
+func TestExtractContainerEnv(t *testing.T) {
+	item := newTestWorkload("apps/v1", "Deployment", "web", map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name": "app",
+				"env": []interface{}{
+					map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+					map[string]interface{}{"name": "DB_PASSWORD", "valueFrom": map[string]interface{}{
+						"secretKeyRef": map[string]interface{}{"name": "db-creds", "key": "password"},
+					}},
+					map[string]interface{}{"name": "POD_NAME", "valueFrom": map[string]interface{}{
+						"fieldRef": map[string]interface{}{"fieldPath": "metadata.name"},
+					}},
+				},
+				"envFrom": []interface{}{
+					map[string]interface{}{"prefix": "APP_", "configMapRef": map[string]interface{}{"name": "app-config"}},
+					map[string]interface{}{"secretRef": map[string]interface{}{"name": "api-keys", "optional": true}},
+				},
+			},
+		},
+	})
+
+	want := []ContainerEnv{{
+		Name: "app",
+		Env: []EnvVar{
+			{Name: "LOG_LEVEL", Value: "info"},
+			{Name: "DB_PASSWORD", ValueFrom: "secretKeyRef:db-creds/password"},
+			{Name: "POD_NAME", ValueFrom: "fieldRef:metadata.name"},
+		},
+		EnvFrom: []EnvFromSource{
+			{Kind: "ConfigMap", Name: "app-config", Prefix: "APP_"},
+			{Kind: "Secret", Name: "api-keys", Optional: true},
+		},
+	}}
+	if got := extractContainerEnv(item); !reflect.DeepEqual(got, want) {
+		t.Errorf("extractContainerEnv() = %+v, want %+v", got, want)
+	}
+
+	rows := formatEnvFromRows("app", EnvFromSource{Kind: "Secret", Name: "api-keys", Optional: true, Missing: true})
+	if want := []string{"app\t*\t<secretRef:api-keys> not found (optional)"}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("formatEnvFromRows() = %q, want %q", rows, want)
+	}
+}
+
+// captureStdout returns what print writes to os.Stdout
+func captureStdout(t *testing.T, print func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	print()
+	w.Close()
+	return <-done
+}
+
+func TestRegisteredCommandsHaveHelp(t *testing.T) {
+	for cmd := range commandExtractors {
+		out := captureStdout(t, func() { printCommandUsage(cmd) })
+		if !strings.HasPrefix(out, "Usage: kubectl getinfo "+cmd+" ") {
+			t.Errorf("command %q has no help text, add it to printCommandUsage", cmd)
+		}
+	}
+	for sub := range schedulingExtractors {
+		want := "Usage: kubectl getinfo scheduling " + sub
+		if sub == "" {
+			want = "Usage: kubectl getinfo scheduling [subcommand]"
+		}
+		out := captureStdout(t, func() { printSchedulingUsage(sub) })
+		if !strings.HasPrefix(out, want) {
+			t.Errorf("scheduling subcommand %q has no help text, add it to printSchedulingUsage", sub)
+		}
+	}
+}
+
+func TestExtractPodOverhead(t *testing.T) {
+	pod := newTestWorkload("v1", "Pod", "sandboxed", map[string]interface{}{
+		"runtimeClassName": "kata",
+		"overhead":         map[string]interface{}{"cpu": "250m", "memory": "120Mi"},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "500m"}}},
+		},
+	})
+	want := map[string]interface{}{"cpu": "250m", "memory": "120Mi"}
+
+	var item OutputItem
+	extractSchedulingSubcommand(pod, &item, "resources")
+	if !reflect.DeepEqual(item.Overhead, want) || len(item.Resources) != 1 {
+		t.Errorf("resources subcommand = overhead %v, %d container(s), want %v and 1 container", item.Overhead, len(item.Resources), want)
+	}
+	if scheduling := extractSchedulingInfo(pod); scheduling == nil || !reflect.DeepEqual(scheduling.Overhead, want) {
+		t.Errorf("extractSchedulingInfo() overhead = %+v, want %v", scheduling, want)
+	}
+
+	// A pod with only an overhead still has something to show
+	onlyOverhead := newTestWorkload("v1", "Pod", "idle", map[string]interface{}{
+		"overhead":   map[string]interface{}{"cpu": "250m"},
+		"containers": []interface{}{map[string]interface{}{"name": "app"}},
+	})
+	item = OutputItem{}
+	extractSchedulingSubcommand(onlyOverhead, &item, "resources")
+	if !hasSchedulingFields(item) {
+		t.Errorf("hasSchedulingFields() = false for a pod with only an overhead")
+	}
+}
+
+func TestExtractResourceClaims(t *testing.T) {
+	job := newTestWorkload("batch/v1", "Job", "train", map[string]interface{}{
+		"resourceClaims": []interface{}{
+			map[string]interface{}{"name": "gpu", "resourceClaimTemplateName": "single-gpu"},
+			map[string]interface{}{"name": "nic", "resourceClaimName": "shared-nic"},
+		},
+		"containers": []interface{}{
+			map[string]interface{}{"name": "trainer", "resources": map[string]interface{}{
+				"claims": []interface{}{map[string]interface{}{"name": "gpu"}},
+			}},
+			map[string]interface{}{"name": "sidecar"},
+		},
+	})
+
+	var item OutputItem
+	extractSchedulingSubcommand(job, &item, "resources")
+	if len(item.ResourceClaims) != 2 {
+		t.Fatalf("resourceClaims = %v, want 2 claims", item.ResourceClaims)
+	}
+	// A container that only uses a claim is listed, one without any resources isn't
+	want := []interface{}{map[string]interface{}{"name": "gpu"}}
+	if len(item.Resources) != 1 || item.Resources[0].Name != "trainer" || !reflect.DeepEqual(item.Resources[0].Claims, want) {
+		t.Errorf("resources = %+v, want trainer with claims %v", item.Resources, want)
+	}
+	if got := formatResourceClaims(item.ResourceClaims); got != "gpu,nic" {
+		t.Errorf("formatResourceClaims() = %q, want %q", got, "gpu,nic")
+	}
+	if got := formatResourceClaims(nil); got != "<none>" {
+		t.Errorf("formatResourceClaims(nil) = %q, want <none>", got)
+	}
+}
+
+func TestSchedulingWithoutConfigIsOmitted(t *testing.T) {
+	pod := newTestWorkload("v1", "Pod", "bare", map[string]interface{}{
+		"containers": []interface{}{map[string]interface{}{"name": "app", "image": "nginx"}},
+	})
+	item := OutputItem{Name: pod.GetName(), Namespace: pod.GetNamespace()}
+	schedulingExtractors[""](pod, &item)
+	if item.Scheduling != nil {
+		t.Fatalf("scheduling = %+v, want nil for a pod without scheduling config", item.Scheduling)
+	}
+	// A node has no namespace
+	output := Output{Items: []OutputItem{item, {Name: "worker-1"}}}
+
+	// Neither format prints an empty scheduling field, nor the namespace of a cluster-scoped item
+	for _, format := range []string{"json", "yaml"} {
+		var buf strings.Builder
+		var err error
+		if format == "json" {
+			err = writeJSON(&buf, output, false)
+		} else {
+			err = writeYAML(&buf, output)
+		}
+		if err != nil {
+			t.Fatalf("%s output error: %v", format, err)
+		}
+		got := buf.String()
+		if strings.Contains(got, "scheduling") || strings.Contains(got, "null") || strings.Count(got, "namespace") != 1 {
+			t.Errorf("%s output = %s, want no scheduling field and a single namespace", format, got)
+		}
+		if !strings.Contains(got, "bare") || !strings.Contains(got, "default") {
+			t.Errorf("%s output = %s, want the pod's name and namespace", format, got)
+		}
+	}
+}
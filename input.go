@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// readObjectsFromFiles reads the objects of every -F source in order, stdin ("-") may be given once
+// Each source may hold several documents, the objects of all of them are returned together
+func readObjectsFromFiles(paths []string) ([]unstructured.Unstructured, error) {
+	stdinCount := 0
+	for _, path := range paths {
+		if path == "-" {
+			stdinCount++
+		}
+	}
+	if stdinCount > 1 {
+		return nil, fmt.Errorf("stdin (-) can only be given once to -F")
+	}
+
+	var objects []unstructured.Unstructured
+	for _, path := range paths {
+		fileObjects, err := readObjectsFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, fileObjects...)
+	}
+	return objects, nil
+}
+
+// readObjectsFromFile reads Kubernetes objects from a YAML or JSON file, or from stdin when path is "-"
+// Multi-document YAML (separated by ---) and concatenated JSON objects are read document by document.
+// List objects (e.g. the output of "kubectl get pods -o json") are expanded into their items
+func readObjectsFromFile(path string) ([]unstructured.Unstructured, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %v", path, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	var objects []unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("error decoding %s: %v", path, err)
+		}
+
+		// Empty documents (a leading or trailing ---, comments only) have no object
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+
+		// Decode numbers as int64 like the dynamic client does, so NestedInt64 works on file input too
+		var object map[string]interface{}
+		if err := utiljson.Unmarshal(raw, &object); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %v", path, err)
+		}
+		if len(object) == 0 {
+			continue
+		}
+		objects = append(objects, expandListObject(unstructured.Unstructured{Object: object})...)
+	}
+
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no objects found in %s", path)
+	}
+	return objects, nil
+}
+
+// expandListObject returns the items of a List object (kind: List, PodList, etc.) or the object itself
+func expandListObject(object unstructured.Unstructured) []unstructured.Unstructured {
+	items, found, _ := unstructured.NestedSlice(object.Object, "items")
+	if !found || !strings.HasSuffix(object.GetKind(), "List") {
+		return []unstructured.Unstructured{object}
+	}
+
+	// Items of typed lists (e.g. PodList) may omit their kind and apiVersion
+	itemKind := strings.TrimSuffix(object.GetKind(), "List")
+
+	var objects []unstructured.Unstructured
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		itemObject := unstructured.Unstructured{Object: itemMap}
+		if itemObject.GetKind() == "" && itemKind != "" {
+			itemObject.SetKind(itemKind)
+			itemObject.SetAPIVersion(object.GetAPIVersion())
+		}
+		objects = append(objects, itemObject)
+	}
+
+	return objects
+}
+
+// filterObjects applies the namespace, resource name and label selector filters to objects read from a file
+// No namespaces or no names means no filtering on that field
+func filterObjects(objects []unstructured.Unstructured, namespaces []string, resourceNames []string, labelSelector labels.Selector) []unstructured.Unstructured {
+	inNamespaces := make(map[string]bool)
+	for _, namespace := range namespaces {
+		inNamespaces[namespace] = true
+	}
+
+	// Position of each requested name, so the result follows the command line order
+	names := make(map[string]int)
+	for i, name := range resourceNames {
+		if _, ok := names[name]; !ok {
+			names[name] = i
+		}
+	}
+
+	var filtered []unstructured.Unstructured
+	for _, object := range objects {
+		if len(inNamespaces) > 0 && !inNamespaces[object.GetNamespace()] {
+			continue
+		}
+		if _, ok := names[object.GetName()]; len(names) > 0 && !ok {
+			continue
+		}
+		if labelSelector != nil && !labelSelector.Matches(labels.Set(object.GetLabels())) {
+			continue
+		}
+		filtered = append(filtered, object)
+	}
+
+	if len(names) > 0 {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return names[filtered[i].GetName()] < names[filtered[j].GetName()]
+		})
+	}
+
+	return filtered
+}
+
+// hasNamespacedObjects checks if any of the objects belongs to a namespace
+func hasNamespacedObjects(objects []unstructured.Unstructured) bool {
+	for _, object := range objects {
+		if object.GetNamespace() != "" {
+			return true
+		}
+	}
+	return false
+}
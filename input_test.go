@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadObjectsFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	multiDoc := filepath.Join(dir, "chart.yaml")
+	content := `---
+apiVersion: v1
+kind: Pod
+metadata: {name: web, namespace: default}
+---
+# empty document
+---
+apiVersion: v1
+kind: PodList
+items:
+- metadata: {name: db, namespace: default}
+`
+	if err := os.WriteFile(multiDoc, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	single := filepath.Join(dir, "api.json")
+	if err := os.WriteFile(single, []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "api"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := readObjectsFromFiles([]string{multiDoc, single})
+	if err != nil {
+		t.Fatalf("readObjectsFromFiles() error = %v", err)
+	}
+	if got := itemNames(objects); !equalStrings(got, []string{"web", "db", "api"}) {
+		t.Errorf("readObjectsFromFiles() = %v, want [web db api]", got)
+	}
+	if objects[1].GetKind() != "Pod" {
+		t.Errorf("list item kind = %q, want Pod", objects[1].GetKind())
+	}
+
+	if _, err := readObjectsFromFiles([]string{"-", single, "-"}); err == nil || !strings.Contains(err.Error(), "only be given once") {
+		t.Errorf("readObjectsFromFiles(-, -) error = %v, want stdin given once", err)
+	}
+}
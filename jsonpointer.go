@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseJSONPointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens
+// "/metadata/annotations/example.com~1owner" gives metadata, annotations and example.com/owner.
+// The empty pointer refers to the whole object and has no tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer '%s': it must be empty or start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		// ~ only escapes ~ (~0) and / (~1)
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON pointer '%s': ~ must be followed by 0 or 1", pointer)
+			}
+		}
+		// ~1 is replaced first, so ~01 gives ~1 and not /
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// resolveJSONPointer returns the value the tokens of a JSON pointer refer to in an unstructured object
+// false means there is no such value: a missing key, an index out of range or a token that isn't an index
+func resolveJSONPointer(object map[string]interface{}, tokens []string) (interface{}, bool) {
+	var current interface{} = object
+	for _, token := range tokens {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			next, ok := value[token]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			// Array indexes are plain decimals without leading zeros, "-" (past the end) never has a value
+			if token == "" || (len(token) > 1 && token[0] == '0') {
+				return nil, false
+			}
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return nil, false
+			}
+			current = value[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONPointer(t *testing.T) {
+	tests := []struct {
+		pointer string
+		want    []string
+		wantErr string
+	}{
+		{pointer: "", want: nil},
+		{pointer: "/metadata/name", want: []string{"metadata", "name"}},
+		{pointer: "/metadata/annotations/example.com~1owner", want: []string{"metadata", "annotations", "example.com/owner"}},
+		{pointer: "/a~0b/~01", want: []string{"a~b", "~1"}},
+		{pointer: "/", want: []string{""}},
+		{pointer: "metadata/name", wantErr: "must be empty or start with /"},
+		{pointer: "/a~2b", wantErr: "~ must be followed by 0 or 1"},
+		{pointer: "/a~", wantErr: "~ must be followed by 0 or 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			got, err := parseJSONPointer(tt.pointer)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseJSONPointer(%q) error = %v, want %q", tt.pointer, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSONPointer(%q) = %q, %v, want %q", tt.pointer, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveJSONPointer(t *testing.T) {
+	object := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"deployment.kubernetes.io/revision": "3"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app"},
+				map[string]interface{}{"name": "proxy"},
+			},
+		},
+	}
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+		wantOK  bool
+	}{
+		{pointer: "/metadata/annotations/deployment.kubernetes.io~1revision", want: "3", wantOK: true},
+		{pointer: "/spec/containers/1/name", want: "proxy", wantOK: true},
+		{pointer: "", want: object, wantOK: true},
+		{pointer: "/spec/containers/2/name"},
+		{pointer: "/spec/containers/01/name"},
+		{pointer: "/spec/containers/-"},
+		{pointer: "/metadata/labels/app"},
+		{pointer: "/metadata/annotations/deployment.kubernetes.io~1revision/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			tokens, err := parseJSONPointer(tt.pointer)
+			if err != nil {
+				t.Fatalf("parseJSONPointer(%q) error = %v", tt.pointer, err)
+			}
+			got, ok := resolveJSONPointer(object, tokens)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveJSONPointer(%q) = %v, %v, want %v, %v", tt.pointer, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -4,67 +4,186 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
-// getKubeconfig returns the Kubernetes REST config
-func getKubeconfig() (*rest.Config, error) {
-	// Try in-cluster config first
-	config, err := rest.InClusterConfig()
-	if err == nil {
-		return config, nil
-	}
-
-	// Try kubeconfig file
+// getKubeconfigPath returns the kubeconfig file from $KUBECONFIG or ~/.kube/config
+func getKubeconfigPath() (string, error) {
 	kubeconfig := os.Getenv("KUBECONFIG")
 	if kubeconfig == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("error getting home directory: %v", err)
+			return "", fmt.Errorf("error getting home directory: %v", err)
 		}
 		kubeconfig = filepath.Join(home, ".kube", "config")
 	}
+	return kubeconfig, nil
+}
+
+// getKubeconfig returns the Kubernetes REST config
+// contextName selects a kubeconfig context (--context), empty means the current context
+// tokenFile (--token-file) replaces the credentials of the context with a bearer token read from a file
+func getKubeconfig(contextName string, tokenFile string) (*rest.Config, error) {
+	// Try in-cluster config first, unless a context was asked for explicitly
+	if contextName == "" {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			return config, useTokenFile(config, tokenFile)
+		}
+	}
+
+	// Try kubeconfig file
+	kubeconfig, err := getKubeconfigPath()
+	if err != nil {
+		return nil, err
+	}
 
-	config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	rawConfig, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubeconfig %s: %v", kubeconfig, err)
+	}
+
+	// Without a context the client would fall back to localhost:8080, explain instead
+	if contextName == "" && rawConfig.CurrentContext == "" {
+		return nil, fmt.Errorf("no current context is set in %s. %s. Pass --context <name> or run: kubectl config use-context <name>",
+			kubeconfig, describeContexts(rawConfig))
+	}
+	if contextName != "" {
+		if err := validateContext(rawConfig, kubeconfig, contextName); err != nil {
+			return nil, err
+		}
+	}
+
+	config, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("error building config from kubeconfig: %v", err)
 	}
 
-	return config, nil
+	return config, useTokenFile(config, tokenFile)
 }
 
-// getCurrentNamespace returns the namespace from the current kubeconfig context
-func getCurrentNamespace() string {
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return "default"
+// useTokenFile makes config authenticate with the bearer token in tokenFile, nothing changes when it is empty
+// client-go rereads the file periodically, so rotated short-lived tokens (e.g. projected service account tokens) keep working
+func useTokenFile(config *rest.Config, tokenFile string) error {
+	if tokenFile == "" {
+		return nil
+	}
+
+	// Fail early with the file name instead of an unauthorized error on the first request
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return fmt.Errorf("error reading token file: %v", err)
+	}
+	if strings.TrimSpace(string(token)) == "" {
+		return fmt.Errorf("token file %s is empty", tokenFile)
+	}
+
+	// The token replaces the other credentials of the context, like kubectl --token
+	config.BearerToken = ""
+	config.BearerTokenFile = tokenFile
+	config.Username = ""
+	config.Password = ""
+	config.AuthProvider = nil
+	config.ExecProvider = nil
+	return nil
+}
+
+// getContextName returns the given context, or the current context of the kubeconfig when empty
+// A given context is checked against the kubeconfig, so a typo isn't printed as a prefix
+func getContextName(contextName string) (string, error) {
+	kubeconfig, err := getKubeconfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	rawConfig, err := clientcmd.LoadFromFile(kubeconfig)
+	if err != nil {
+		return "", fmt.Errorf("error loading kubeconfig %s: %v", kubeconfig, err)
+	}
+	if contextName != "" {
+		if err := validateContext(rawConfig, kubeconfig, contextName); err != nil {
+			return "", err
 		}
-		kubeconfig = filepath.Join(home, ".kube", "config")
+		return contextName, nil
+	}
+	if rawConfig.CurrentContext == "" {
+		return "", fmt.Errorf("no current context is set in %s, pass --context <name>", kubeconfig)
+	}
+
+	return rawConfig.CurrentContext, nil
+}
+
+// validateContext checks that a context exists in the kubeconfig and that its cluster and user are defined
+// clientcmd reports these cases with errors that don't say which name is wrong
+func validateContext(rawConfig *clientcmdapi.Config, kubeconfig, contextName string) error {
+	context, exists := rawConfig.Contexts[contextName]
+	if !exists || context == nil {
+		return fmt.Errorf("context %q not found in %s. %s", contextName, kubeconfig, describeContexts(rawConfig))
+	}
+	if _, exists := rawConfig.Clusters[context.Cluster]; !exists {
+		return fmt.Errorf("context %q refers to cluster %q, which is not defined in %s", contextName, context.Cluster, kubeconfig)
+	}
+	if context.AuthInfo != "" {
+		if _, exists := rawConfig.AuthInfos[context.AuthInfo]; !exists {
+			return fmt.Errorf("context %q refers to user %q, which is not defined in %s", contextName, context.AuthInfo, kubeconfig)
+		}
+	}
+	return nil
+}
+
+// describeContexts lists the contexts defined in a kubeconfig for error messages
+func describeContexts(rawConfig *clientcmdapi.Config) string {
+	if len(rawConfig.Contexts) == 0 {
+		return "The kubeconfig defines no contexts"
+	}
+
+	names := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "Available contexts: " + strings.Join(names, ", ")
+}
+
+// getCurrentNamespace returns the namespace from the given kubeconfig context (or the current one when empty)
+func getCurrentNamespace(contextName string) string {
+	namespace, _ := resolveCurrentNamespace(contextName)
+	return namespace
+}
+
+// resolveCurrentNamespace returns the namespace of the given kubeconfig context (or the current one when empty)
+// and how it was found, for --show-namespace-source. Falls back to "default" when the context sets none.
+func resolveCurrentNamespace(contextName string) (string, string) {
+	kubeconfig, err := getKubeconfigPath()
+	if err != nil {
+		return "default", "fallback, no kubeconfig found"
 	}
 
 	config, err := clientcmd.LoadFromFile(kubeconfig)
 	if err != nil {
-		return "default"
+		return "default", fmt.Sprintf("fallback, %s could not be read", kubeconfig)
 	}
 
-	contextName := config.CurrentContext
 	if contextName == "" {
-		return "default"
+		contextName = config.CurrentContext
+	}
+	if contextName == "" {
+		return "default", fmt.Sprintf("fallback, %s has no current context", kubeconfig)
 	}
 
 	context, exists := config.Contexts[contextName]
 	if !exists || context == nil {
-		return "default"
+		return "default", fmt.Sprintf("fallback, context '%s' not found in %s", contextName, kubeconfig)
 	}
 
 	if context.Namespace != "" {
-		return context.Namespace
+		return context.Namespace, fmt.Sprintf("namespace of context '%s' in %s", contextName, kubeconfig)
 	}
 
-	return "default"
+	return "default", fmt.Sprintf("fallback, context '%s' sets no namespace", contextName)
 }
-
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestValidateContext(t *testing.T) {
+	rawConfig := &clientcmdapi.Config{
+		Clusters:  map[string]*clientcmdapi.Cluster{"kind": {Server: "https://127.0.0.1:6443"}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"admin": {}},
+		Contexts: map[string]*clientcmdapi.Context{
+			"dev":     {Cluster: "kind", AuthInfo: "admin"},
+			"prod":    {Cluster: "kind", AuthInfo: "admin"},
+			"broken":  {Cluster: "gone", AuthInfo: "admin"},
+			"no-user": {Cluster: "kind", AuthInfo: "nobody"},
+		},
+	}
+
+	tests := []struct {
+		context string
+		wantErr string
+	}{
+		{context: "dev"},
+		{context: "prdo", wantErr: `context "prdo" not found in config. Available contexts: broken, dev, no-user, prod`},
+		{context: "broken", wantErr: `refers to cluster "gone"`},
+		{context: "no-user", wantErr: `refers to user "nobody"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			err := validateContext(rawConfig, "config", tt.context)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateContext() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateContext() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUseTokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("projected-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &rest.Config{
+		BearerToken:  "from-kubeconfig",
+		ExecProvider: &clientcmdapi.ExecConfig{Command: "aws"},
+	}
+	if err := useTokenFile(config, tokenFile); err != nil {
+		t.Fatalf("useTokenFile() error = %v", err)
+	}
+	if config.BearerTokenFile != tokenFile || config.BearerToken != "" || config.ExecProvider != nil {
+		t.Errorf("useTokenFile() left BearerTokenFile=%q BearerToken=%q ExecProvider=%v", config.BearerTokenFile, config.BearerToken, config.ExecProvider)
+	}
+
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := useTokenFile(&rest.Config{}, emptyFile); err == nil {
+		t.Error("useTokenFile() with an empty file, want error")
+	}
+	if err := useTokenFile(&rest.Config{}, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("useTokenFile() with a missing file, want error")
+	}
+}
+
+func TestResolveCurrentNamespace(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	content := `apiVersion: v1
+kind: Config
+clusters: [{name: kind, cluster: {server: "https://127.0.0.1:6443"}}]
+users: [{name: admin, user: {}}]
+contexts:
+- {name: dev, context: {cluster: kind, user: admin, namespace: team-a}}
+- {name: prod, context: {cluster: kind, user: admin}}
+current-context: dev
+`
+	if err := os.WriteFile(kubeconfig, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	tests := []struct {
+		context       string
+		wantNamespace string
+		wantSource    string
+	}{
+		{context: "", wantNamespace: "team-a", wantSource: "namespace of context 'dev'"},
+		{context: "prod", wantNamespace: "default", wantSource: "fallback, context 'prod' sets no namespace"},
+		{context: "gone", wantNamespace: "default", wantSource: "fallback, context 'gone' not found"},
+	}
+	for _, tt := range tests {
+		namespace, source := resolveCurrentNamespace(tt.context)
+		if namespace != tt.wantNamespace || !strings.Contains(source, tt.wantSource) {
+			t.Errorf("resolveCurrentNamespace(%q) = %q, %q, want %q, %q", tt.context, namespace, source, tt.wantNamespace, tt.wantSource)
+		}
+	}
+}
@@ -1,32 +1,652 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
-	"gopkg.in/yaml.v3"
+	"golang.org/x/term"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 )
 
+// managedByLabel is the well-known label naming the tool that manages a resource (used by --managed-by)
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// Exit codes of --strict-exit-codes, so CI pipelines can branch on the failure reason
+// Without the flag every error exits with 1
+const (
+	exitNoResults    = 2
+	exitNotFound     = 3
+	exitForbidden    = 4
+	exitConnection   = 5
+	exitInvalidFlags = 6
+)
+
+// errorExitCode returns the exit code for an error, 1 unless strict exit codes tell the error classes apart
+func errorExitCode(err error, strict bool) int {
+	if !strict {
+		return 1
+	}
+
+	// An aggregate gets the code shared by all its errors, 1 when they differ
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		code := 0
+		for _, e := range aggregate.Errors() {
+			c := errorExitCode(e, strict)
+			if code != 0 && c != code {
+				return 1
+			}
+			code = c
+		}
+		if code != 0 {
+			return code
+		}
+		return 1
+	}
+
+	var typeNotFound *resourceTypeNotFoundError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &typeNotFound) || apierrors.IsNotFound(err):
+		return exitNotFound
+	case errors.Is(err, errForbidden) || apierrors.IsForbidden(err):
+		return exitForbidden
+	case errors.Is(err, errDiscoveryFailed) || errors.As(err, &netErr):
+		return exitConnection
+	case errors.As(err, new(invalidFlagsError)):
+		return exitInvalidFlags
+	}
+	return 1
+}
+
+// printErrors writes an error to stderr after prefix, an aggregate gets one line per error
+func printErrors(prefix string, err error) {
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		for _, e := range utilerrors.Flatten(aggregate).Errors() {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, e)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, err)
+}
+
+// invalidFlagsError is returned by validateFlags for flags that don't work together or with the output format
+type invalidFlagsError string
+
+func (e invalidFlagsError) Error() string {
+	return string(e)
+}
+
+// outputFlags holds the flags checked by validateFlags
+type outputFlags struct {
+	cmdType string
+	// format is the output format without its template (see splitOutputTemplate)
+	format               string
+	snapshot             bool
+	snapshotFile         string
+	watch                bool
+	watchTimeout         time.Duration
+	countByKind          bool
+	countUnique          string
+	diffNamespace        bool
+	dedupe               bool
+	dedupeIdentical      bool
+	asMap                bool
+	nestByNamespace      bool
+	unwrapSingle         bool
+	managedFieldsSummary bool
+	jsonPointers         []string
+	showSpecPath         bool
+	contextPrefix        bool
+	fullGVK              bool
+	wide                 bool
+	tableLayout          string
+	maxColWidth          int
+	abbrevNamespace      bool
+	explain              bool
+	groupByNamespace     bool
+	groupByAnnotation    string
+	excelCompat          bool
+	color                bool
+	legend               bool
+	labelColumns         []string
+
+	// The flags below are checked against the command and the source of the objects
+	subCommand             string
+	resourceType           string
+	fromFile               bool
+	namespace              string
+	allNamespaces          bool
+	scope                  string
+	fieldSelector          bool
+	sinceRevision          bool
+	fromCache              bool
+	concurrency            int
+	context                bool
+	tokenFile              bool
+	includeUnavailable     bool
+	withUsage              bool
+	resolvePriority        bool
+	redact                 bool
+	inheritNamespaceLabels bool
+	expandRefs             bool
+	// resourceNames is set when names are fetched one by one (not --glob patterns)
+	resourceNames bool
+}
+
+// validateFlags rejects flags that would be silently ignored or produce garbage with the chosen output
+// It only looks at the flags, so it runs before anything is asked from the API server
+func validateFlags(flags outputFlags) error {
+	isFormat := func(formats ...string) bool {
+		for _, format := range formats {
+			if flags.format == format {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !isFormat("json", "jsonl", "yaml", "table", "csv", "tsv", "name", "otel", "jsonpath", "go-template") {
+		supported := "json, jsonl, yaml, table, csv, tsv, name, otel, jsonpath=<template>, go-template=<template>"
+		if !supportsTable(flags.cmdType) {
+			supported = "json, jsonl, yaml, csv, tsv, name, otel, jsonpath=<template>, go-template=<template>"
+		}
+		return invalidFlagsError(fmt.Sprintf("unsupported output format '%s'. Supported formats: %s", flags.format, supported))
+	}
+	// Flags that print counts or a report instead of the resources
+	var countFlags []string
+	for _, countFlag := range []struct {
+		set  bool
+		name string
+	}{
+		{flags.countByKind, "--count-by-kind"},
+		{flags.dedupe, "--dedupe"},
+		{flags.dedupeIdentical, "--dedupe-identical"},
+		{flags.countUnique != "", "--count-unique"},
+		{flags.diffNamespace, "--diff-namespace"},
+	} {
+		if countFlag.set {
+			countFlags = append(countFlags, countFlag.name)
+		}
+	}
+
+	// The label and annotation counts and the namespace comparison have a table of their own,
+	// and so do labels shown as columns
+	if flags.format == "table" && !supportsTable(flags.cmdType) && len(countFlags) == 0 && len(flags.labelColumns) == 0 {
+		return invalidFlagsError(fmt.Sprintf("table format is not supported for '%s' command. Supported formats: json, yaml", flags.cmdType))
+	}
+
+	// Events are streamed as they arrive, so only a line-per-record format fits
+	if flags.watch {
+		if flags.format != "jsonl" {
+			return invalidFlagsError("--watch is only supported with jsonl output (-o jsonl)")
+		}
+		if flags.snapshot || len(countFlags) > 0 {
+			return invalidFlagsError("--watch cannot be used with snapshot, --count-by-kind, --dedupe, --dedupe-identical, --count-unique or --diff-namespace")
+		}
+	}
+	if flags.watchTimeout < 0 {
+		return invalidFlagsError("--watch-timeout must not be negative")
+	}
+	if flags.watchTimeout > 0 && !flags.watch {
+		return invalidFlagsError("--watch-timeout is only supported with --watch")
+	}
+
+	if flags.snapshotFile != "" && !flags.snapshot {
+		return invalidFlagsError("--snapshot-file is only supported by the snapshot command")
+	}
+	// Snapshots save the items as they are, the output shape flags don't apply
+	if flags.snapshot {
+		if len(countFlags) > 0 {
+			return invalidFlagsError("snapshot saves the items and cannot be used with --count-by-kind, --dedupe, --dedupe-identical, --count-unique or --diff-namespace")
+		}
+		if flags.asMap || flags.nestByNamespace || flags.unwrapSingle || flags.contextPrefix {
+			return invalidFlagsError("snapshot saves the items as a list and cannot be used with --as-map, --nest-by-namespace, --unwrap-single or --context-prefix")
+		}
+	}
+
+	// The counts replace the per-resource output, flags shaping that output would be ignored
+	if len(countFlags) > 0 {
+		flag := countFlags[0]
+		if len(countFlags) > 1 {
+			return invalidFlagsError(fmt.Sprintf("%s cannot be used together", strings.Join(countFlags, " and ")))
+		}
+		if !isFormat("json", "yaml", "table") {
+			return invalidFlagsError(fmt.Sprintf("%s is only supported with json, yaml and table output", flag))
+		}
+		shapeFlags := []struct {
+			set  bool
+			name string
+		}{
+			{flags.asMap, "--as-map"},
+			{flags.nestByNamespace, "--nest-by-namespace"},
+			{flags.unwrapSingle, "--unwrap-single"},
+			{flags.managedFieldsSummary, "--managed-fields-summary"},
+			{len(flags.jsonPointers) > 0, "--json-pointer"},
+			{flags.showSpecPath, "--show-spec-path"},
+			{flags.groupByNamespace, "--group-by-namespace"},
+			{flags.groupByAnnotation != "", "--group-by-annotation"},
+			{flags.fullGVK, "--full-gvk"},
+			{flags.wide, "--wide"},
+			{flags.tableLayout == "merged", "--table-layout"},
+			{flags.maxColWidth > 0, "--max-col-width"},
+			{flags.abbrevNamespace, "--abbrev-namespace"},
+		}
+		for _, shapeFlag := range shapeFlags {
+			if shapeFlag.set {
+				return invalidFlagsError(fmt.Sprintf("%s prints counts instead of resources and cannot be used with %s", flag, shapeFlag.name))
+			}
+		}
+	}
+
+	if flags.wide && !isFormat("table", "csv", "tsv") {
+		return invalidFlagsError("--wide is only supported with table, csv and tsv output")
+	}
+	switch flags.tableLayout {
+	case "", "default":
+	case "merged":
+		if !isFormat("table", "csv", "tsv") {
+			return invalidFlagsError("--table-layout is only supported with table, csv and tsv output")
+		}
+	default:
+		return invalidFlagsError(fmt.Sprintf("unknown --table-layout '%s', use default or merged", flags.tableLayout))
+	}
+	if flags.fullGVK && !isFormat("table", "csv", "tsv") {
+		return invalidFlagsError("--full-gvk is only supported with table, csv and tsv output, json and yaml always include the apiVersion")
+	}
+	if flags.maxColWidth < 0 {
+		return invalidFlagsError("--max-col-width must not be negative")
+	}
+	if flags.concurrency < 0 {
+		return invalidFlagsError("--concurrency must not be negative")
+	}
+	if flags.maxColWidth > 0 && flags.format != "table" {
+		return invalidFlagsError("--max-col-width is only supported with table output")
+	}
+	if flags.abbrevNamespace {
+		if flags.format != "table" {
+			return invalidFlagsError("--abbrev-namespace is only supported with table output")
+		}
+		if flags.groupByNamespace {
+			return invalidFlagsError("--abbrev-namespace and --group-by-namespace cannot be used together, grouped tables have no NAMESPACE column")
+		}
+	}
+	if flags.groupByNamespace && flags.format != "table" {
+		return invalidFlagsError("--group-by-namespace is only supported with table output, use --nest-by-namespace with json and yaml")
+	}
+	if flags.groupByAnnotation != "" {
+		if flags.format != "table" {
+			return invalidFlagsError("--group-by-annotation is only supported with table output")
+		}
+		if flags.groupByNamespace {
+			return invalidFlagsError("--group-by-annotation and --group-by-namespace cannot be used together")
+		}
+	}
+	if flags.explain {
+		if !isFormat("yaml", "table") {
+			return invalidFlagsError("--explain is only supported with yaml and table output")
+		}
+		if flags.snapshot {
+			return invalidFlagsError("snapshot saves the items and cannot be used with --explain")
+		}
+	}
+	// The legend explains the colors, the outputs without colors have nothing to explain
+	if flags.legend {
+		if !flags.color {
+			return invalidFlagsError("--legend is only shown with colored output, add -c")
+		}
+		if !isFormat("json", "otel", "table") {
+			return invalidFlagsError("--legend is only supported with json, otel and table output")
+		}
+		if flags.format == "table" && len(countFlags) > 0 {
+			return invalidFlagsError(fmt.Sprintf("--legend cannot be used with %s, its table is not colored", countFlags[0]))
+		}
+	}
+	if len(flags.labelColumns) > 0 {
+		if !isFormat("table", "csv", "tsv") {
+			return invalidFlagsError("--label-columns is only supported with table, csv and tsv output")
+		}
+		if len(countFlags) > 0 {
+			return invalidFlagsError(fmt.Sprintf("--label-columns cannot be used with %s", countFlags[0]))
+		}
+	}
+	if flags.excelCompat && !isFormat("csv", "tsv") {
+		return invalidFlagsError("--excel-compat is only supported with csv and tsv output")
+	}
+	if flags.asMap && !isFormat("json", "yaml") {
+		return invalidFlagsError("--as-map is only supported with json and yaml output")
+	}
+	if flags.nestByNamespace {
+		if !isFormat("json", "yaml") {
+			return invalidFlagsError("--nest-by-namespace is only supported with json and yaml output")
+		}
+		if flags.asMap {
+			return invalidFlagsError("--nest-by-namespace and --as-map cannot be used together")
+		}
+	}
+	if flags.unwrapSingle {
+		if !isFormat("json", "yaml") {
+			return invalidFlagsError("--unwrap-single is only supported with json and yaml output")
+		}
+		if flags.asMap || flags.nestByNamespace {
+			return invalidFlagsError("--unwrap-single cannot be used with --as-map or --nest-by-namespace")
+		}
+	}
+	if flags.contextPrefix && flags.format != "name" {
+		return invalidFlagsError("--context-prefix is only supported with name output")
+	}
+	if flags.managedFieldsSummary && !isFormat("json", "yaml") {
+		return invalidFlagsError("--managed-fields-summary is only supported with json and yaml output")
+	}
+	if flags.showSpecPath && !isFormat("json", "yaml", "jsonl") {
+		return invalidFlagsError("--show-spec-path is only supported with json, yaml and jsonl output")
+	}
+	// Table, csv and tsv columns have no room for the usage of every container
+	if flags.withUsage && !isFormat("json", "yaml", "jsonl", "jsonpath", "go-template") {
+		return invalidFlagsError("--with-usage is only supported with json, yaml, jsonl and template output")
+	}
+	if len(flags.jsonPointers) > 0 && !isFormat("json", "yaml", "jsonl", "jsonpath", "go-template") {
+		return invalidFlagsError("--json-pointer is only supported with json, yaml, jsonl and template output")
+	}
+	for _, pointer := range flags.jsonPointers {
+		if _, err := parseJSONPointer(pointer); err != nil {
+			return invalidFlagsError(err.Error())
+		}
+	}
+	// Pointers read the raw object, the annotations they reach would not be redacted
+	if flags.redact {
+		for _, pointer := range flags.jsonPointers {
+			if reachesAnnotations(pointer) {
+				return invalidFlagsError(fmt.Sprintf("--json-pointer '%s' reaches the annotations and cannot be used with --redact", pointer))
+			}
+		}
+	}
+	if err := validateCommandFlags(flags); err != nil {
+		return err
+	}
+	return validateSourceFlags(flags)
+}
+
+// validateCommandFlags rejects flags that only apply to some commands
+func validateCommandFlags(flags outputFlags) error {
+	isSubcommand := func(subCommand string) bool {
+		return flags.cmdType == "scheduling" && flags.subCommand == subCommand
+	}
+
+	if flags.countByKind && flags.cmdType != "owner" {
+		return invalidFlagsError("--count-by-kind is only supported for 'owner' command")
+	}
+	if flags.sinceRevision && flags.cmdType != "owner" {
+		return invalidFlagsError("--since-revision is only supported for 'owner' command")
+	}
+	if flags.countUnique != "" && flags.cmdType != "labels" {
+		return invalidFlagsError("--count-unique is only supported for 'labels' command")
+	}
+	if flags.diffNamespace {
+		if flags.cmdType != "labels" {
+			return invalidFlagsError("--diff-namespace is only supported for 'labels' command")
+		}
+		if flags.allNamespaces || len(splitNamespaces(flags.namespace)) != 2 {
+			return invalidFlagsError("--diff-namespace compares two namespaces, pass them with -n (e.g. -n staging,prod)")
+		}
+	}
+	if flags.dedupe && flags.cmdType != "owner" {
+		return invalidFlagsError("--dedupe is only supported for 'owner' command")
+	}
+	if flags.dedupeIdentical && flags.cmdType != "labels" && flags.cmdType != "annotations" {
+		return invalidFlagsError("--dedupe-identical is only supported for 'labels' and 'annotations' commands")
+	}
+	if flags.withUsage && !isSubcommand("resources") {
+		return invalidFlagsError("--with-usage is only supported for 'scheduling resources' command")
+	}
+	if flags.resolvePriority && !isSubcommand("priority") {
+		return invalidFlagsError("--resolve-priority is only supported for 'scheduling priority' command")
+	}
+	if flags.tableLayout == "merged" && !isSubcommand("resources") {
+		return invalidFlagsError("--table-layout=merged is only supported for 'scheduling resources'")
+	}
+	if flags.explain && flags.cmdType != "scheduling" {
+		return invalidFlagsError("--explain is only supported for 'scheduling' command")
+	}
+	if flags.redact && flags.cmdType != "annotations" {
+		return invalidFlagsError("--redact is only supported for 'annotations' command")
+	}
+	if len(flags.labelColumns) > 0 && flags.cmdType != "labels" {
+		return invalidFlagsError("--label-columns is only supported for 'labels' command")
+	}
+	if flags.inheritNamespaceLabels && flags.cmdType != "labels" {
+		return invalidFlagsError("--inherit-namespace-labels is only supported for 'labels' command")
+	}
+	if flags.expandRefs && flags.cmdType != "env" {
+		return invalidFlagsError("--expand-refs is only supported for 'env' command")
+	}
+	return nil
+}
+
+// validateSourceFlags rejects flags that don't fit where the objects come from: the cluster, or files (-F)
+func validateSourceFlags(flags outputFlags) error {
+	// Named resources are fetched with a get, which the API server doesn't filter with a field selector (a watch lists them)
+	if flags.fieldSelector && flags.resourceNames && !flags.watch {
+		return invalidFlagsError("field selectors only apply to lists and cannot be used with resource names, use --glob patterns to filter by name")
+	}
+	if flags.watch {
+		if flags.fromFile {
+			return invalidFlagsError("--watch needs to query the cluster and cannot be used with -F")
+		}
+		if flags.fromCache || flags.withUsage {
+			return invalidFlagsError("--watch cannot be used with --from-cache or --with-usage")
+		}
+		if len(splitNamespaces(flags.namespace)) > 1 {
+			return invalidFlagsError(fmt.Sprintf("--watch needs a single namespace (or -A), got -n %s", flags.namespace))
+		}
+	}
+
+	switch flags.scope {
+	case "", scopeAll:
+	case scopeNamespaced, scopeCluster:
+		if flags.resourceType != allResourceTypes {
+			return invalidFlagsError("--scope only applies to the 'all' resource type")
+		}
+	default:
+		return invalidFlagsError(fmt.Sprintf("--scope must be 'namespaced', 'cluster' or 'all', got '%s'", flags.scope))
+	}
+	if flags.nestByNamespace && flags.scope == scopeCluster {
+		return invalidFlagsError("--nest-by-namespace requires a namespaced resource type")
+	}
+
+	if !flags.fromFile {
+		return nil
+	}
+	// Objects read from a file carry no context, the name has to come from the kubeconfig
+	if flags.contextPrefix && !flags.context {
+		return invalidFlagsError("--context-prefix with -F requires --context")
+	}
+	if flags.resourceType != "" {
+		return invalidFlagsError("resource type cannot be combined with -F, the objects are read from the file")
+	}
+	clusterFlags := []struct {
+		set  bool
+		name string
+	}{
+		{flags.fromCache, "--from-cache only applies to cluster queries"},
+		{flags.tokenFile, "--token-file only applies to cluster queries"},
+		{flags.includeUnavailable, "--include-unavailable-groups only applies to cluster queries"},
+		{flags.sinceRevision, "--since-revision needs to query the cluster"},
+		{flags.expandRefs, "--expand-refs needs to query the cluster"},
+		{flags.inheritNamespaceLabels, "--inherit-namespace-labels needs to query the cluster"},
+		{flags.resolvePriority, "--resolve-priority needs to query the cluster"},
+		{flags.withUsage, "--with-usage needs to query the metrics API"},
+		{flags.cmdType == "pdb", "'pdb' command needs to query the cluster"},
+		{flags.fieldSelector, "field selectors are evaluated by the API server"},
+	}
+	for _, clusterFlag := range clusterFlags {
+		if clusterFlag.set {
+			return invalidFlagsError(clusterFlag.name + " and cannot be used with -F")
+		}
+	}
+	return nil
+}
+
+// validateNamespacedFlags rejects flags that need namespaced objects
+// Whether the objects are namespaced is only known once discovery or the files (-F) tell, so it runs after validateFlags
+func validateNamespacedFlags(flags outputFlags, namespaced bool) error {
+	if flags.nestByNamespace && !namespaced {
+		return invalidFlagsError("--nest-by-namespace requires a namespaced resource type")
+	}
+	return nil
+}
+
 // isSchedulingSubcommand checks if the given command is a valid scheduling subcommand
 func isSchedulingSubcommand(cmd string) bool {
-	validSubcommands := []string{
-		"tolerations", "affinity", "nodeselector",
-		"resources", "topology", "priority", "runtime",
+	_, ok := schedulingExtractors[cmd]
+	return ok && cmd != ""
+}
+
+// isCommand checks if the given command is a valid resource command (other than scheduling)
+func isCommand(cmd string) bool {
+	_, ok := commandExtractors[cmd]
+	return ok
+}
+
+// targetNamespace returns the namespace to query ("" for all namespaces) and where it comes from
+// Without -n or -A, namespaced resources are looked up in the namespace of the kubeconfig context,
+// unless noFallback (--no-fallback-namespace) requires the namespace to be explicit.
+func targetNamespace(namespace string, allNamespaces, namespaced, noFallback bool, kubeContext string) (string, string, error) {
+	switch {
+	case allNamespaces:
+		return "", "-A", nil
+	case namespace != "" || !namespaced:
+		return namespace, "-n", nil
+	case noFallback:
+		// Scripts may want an explicit namespace rather than whatever the kubeconfig points at
+		return "", "", errors.New("namespace required, pass -n <namespace> or -A (--no-fallback-namespace is set)")
+	}
+	namespace, source := resolveCurrentNamespace(kubeContext)
+	return namespace, source, nil
+}
+
+// needsOnlyMetadata checks if a command reads nothing but metadata (labels, annotations, owners)
+// so resources can be fetched without their spec and status
+func needsOnlyMetadata(cmdType string) bool {
+	metadataCommands := []string{"labels", "annotations", "owner", "revision", "finalizers"}
+	for _, v := range metadataCommands {
+		if cmdType == v {
+			return true
+		}
+	}
+	return false
+}
+
+// pointsIntoMetadata checks if every JSON pointer stays within what the metadata-only client returns
+func pointsIntoMetadata(pointers []string) bool {
+	for _, pointer := range pointers {
+		tokens, _ := parseJSONPointer(pointer)
+		if len(tokens) == 0 || (tokens[0] != "metadata" && tokens[0] != "apiVersion" && tokens[0] != "kind") {
+			return false
+		}
+	}
+	return true
+}
+
+// reachesAnnotations checks if a JSON pointer returns annotation values: the annotations themselves
+// or one of the objects holding them (the whole object, metadata)
+func reachesAnnotations(pointer string) bool {
+	tokens, _ := parseJSONPointer(pointer)
+	annotationsPath := []string{"metadata", "annotations"}
+	for i := 0; i < len(tokens) && i < len(annotationsPath); i++ {
+		if tokens[i] != annotationsPath[i] {
+			return false
+		}
 	}
-	for _, v := range validSubcommands {
-		if cmd == v {
+	return true
+}
+
+// supportsTable checks if the given command supports table output
+func supportsTable(cmdType string) bool {
+	tableCommands := []string{"owner", "pdb", "command", "lifecycle", "revision", "identity", "replicas", "service", "finalizers", "network", "hooks", "volumes", "readiness", "env", "scheduling"}
+	for _, v := range tableCommands {
+		if cmdType == v {
 			return true
 		}
 	}
 	return false
 }
 
+// strictFlag is --strict, honored anywhere on the command line since it is about the flags around it
+const strictFlag = "--strict"
+
+// checkStrictArgs rejects the arguments left after parsing that look like flags (--strict)
+// Parsing stops at the first resource name, so flags after the names and mistyped flags (--namesapce)
+// would otherwise be looked up as resources. Resource names never start with "-", so nothing valid is lost.
+func checkStrictArgs(fs *flag.FlagSet, args []string) error {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if arg == "-" || arg == "--" {
+			return invalidFlagsError(fmt.Sprintf("'%s' after the resource names would be read as a resource name (--strict)", arg))
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if fs.Lookup(name) != nil {
+			return invalidFlagsError(fmt.Sprintf("flag %s comes after the resource names and would be read as a resource name, put it before them (--strict)", arg))
+		}
+		return invalidFlagsError(fmt.Sprintf("unknown flag %s (--strict)", arg))
+	}
+	return nil
+}
+
+// repeatedFlag collects the values of a flag that may be given several times
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// flagPassed checks if one of the given flags was set on the command line, rather than left at its default
+func flagPassed(fs *flag.FlagSet, names ...string) bool {
+	passed := false
+	fs.Visit(func(f *flag.Flag) {
+		for _, name := range names {
+			if f.Name == name {
+				passed = true
+			}
+		}
+	})
+	return passed
+}
+
 // isHelpFlag checks if the argument is a help flag
 func isHelpFlag(arg string) bool {
 	return arg == "-h" || arg == "--help" || arg == "-help"
@@ -50,12 +670,16 @@ func preprocessArgs(args []string) []string {
 		"-o": true,
 		"-n": true,
 		"-l": true,
+		"-F": true,
+		"-v": true,
+		"-L": true,
 	}
 
 	// Short boolean flags (for combining like -Ac)
 	boolFlags := map[string]bool{
 		"-A": true,
 		"-c": true,
+		"-w": true,
 	}
 
 	var result []string
@@ -67,52 +691,439 @@ func preprocessArgs(args []string) []string {
 			continue
 		}
 
-		// Skip long flags (they work as expected)
-		if strings.HasPrefix(arg, "--") {
-			result = append(result, arg)
+		// Skip long flags (they work as expected)
+		if strings.HasPrefix(arg, "--") {
+			result = append(result, arg)
+			continue
+		}
+
+		// Check if it's a short string flag with attached value (e.g., -oyaml)
+		expanded := false
+		for flag := range stringFlags {
+			if strings.HasPrefix(arg, flag) && len(arg) > len(flag) {
+				// Check if the character after flag is not '=' (handled by flag package)
+				if arg[len(flag)] != '=' {
+					// Split: -oyaml -> -o yaml
+					result = append(result, flag, arg[len(flag):])
+					expanded = true
+					break
+				}
+			}
+		}
+
+		if expanded {
+			continue
+		}
+
+		// Check for combined boolean flags (e.g., -Ac -> -A -c)
+		if len(arg) > 2 {
+			allBool := true
+			for i := 1; i < len(arg); i++ {
+				shortFlag := "-" + string(arg[i])
+				if !boolFlags[shortFlag] {
+					allBool = false
+					break
+				}
+			}
+			if allBool {
+				for i := 1; i < len(arg); i++ {
+					result = append(result, "-"+string(arg[i]))
+				}
+				continue
+			}
+		}
+
+		// No expansion needed
+		result = append(result, arg)
+	}
+
+	return result
+}
+
+// resourceTypeName returns the lowercase kind qualified by its API group, like kubectl's name output
+// Core resources have no group (pod), the others do (deployment.apps)
+func resourceTypeName(item unstructured.Unstructured) string {
+	groupKind := item.GroupVersionKind().GroupKind()
+	if groupKind.Group == "" {
+		return strings.ToLower(groupKind.Kind)
+	}
+	return strings.ToLower(groupKind.Kind) + "." + groupKind.Group
+}
+
+// hasPodSpec checks if a resource is a Pod or has a pod template
+// getPodSpecPath falls back to plain "spec" for every other kind
+func hasPodSpec(item unstructured.Unstructured) bool {
+	return item.GetKind() == "Pod" || len(getPodSpecPath(item)) > 1
+}
+
+// hasSchedulingFields checks if any scheduling field was extracted for an item
+func hasSchedulingFields(item OutputItem) bool {
+	return item.Scheduling != nil || len(item.Tolerations) > 0 || len(item.Affinity) > 0 ||
+		len(item.NodeSelector) > 0 || len(item.Resources) > 0 || len(item.Overhead) > 0 || len(item.ResourceClaims) > 0 ||
+		len(item.TopologySpreadConstraints) > 0 ||
+		len(item.Priority) > 0 || len(item.Runtime) > 0
+}
+
+// hasNonEmptyResult tells whether the field shown by the command is set on an item (--non-empty)
+// For scheduling, fields the API server fills in for every pod (nodeName, schedulerName, priority) don't count.
+// Commands whose fields always have a value (lifecycle, identity, replicas, service, network) keep every item
+func hasNonEmptyResult(item OutputItem, cmdType string) bool {
+	switch cmdType {
+	case "labels":
+		return item.Labels != nil && len(*item.Labels) > 0
+	case "annotations":
+		return item.Annotations != nil && len(*item.Annotations) > 0
+	case "owner":
+		return len(item.OwnerReferences) > 0
+	case "pdb":
+		return len(item.PodDisruptionBudgets) > 0
+	case "command":
+		for _, command := range item.Commands {
+			if len(command.Command) > 0 || len(command.Args) > 0 {
+				return true
+			}
+		}
+		return false
+	case "env":
+		for _, container := range item.Env {
+			if len(container.Env) > 0 || len(container.EnvFrom) > 0 {
+				return true
+			}
+		}
+		return false
+	case "hooks":
+		for _, hooks := range item.Hooks {
+			if hooks.PostStart != nil || hooks.PreStop != nil {
+				return true
+			}
+		}
+		return false
+	case "volumes":
+		return len(item.Volumes) > 0 || len(item.VolumeClaimTemplates) > 0
+	case "readiness":
+		return len(item.ReadinessGates) > 0
+	case "revision":
+		return item.Revision != nil && (item.Revision.Revision != "" || item.Revision.ChangeCause != "")
+	case "finalizers":
+		return item.Finalizers != nil && (len(item.Finalizers.Finalizers) > 0 || item.Finalizers.DeletionTimestamp != "")
+	case "scheduling":
+		if scheduling := item.Scheduling; scheduling != nil {
+			return len(scheduling.NodeSelector) > 0 || len(scheduling.Affinity) > 0 || len(scheduling.Tolerations) > 0 ||
+				len(scheduling.TopologySpreadConstraints) > 0 || len(scheduling.ResourceRequests) > 0 ||
+				len(scheduling.ResourceLimits) > 0 || len(scheduling.Overhead) > 0 || scheduling.PriorityClassName != "" || scheduling.RuntimeClassName != "" ||
+				scheduling.HostNetwork || scheduling.HostPID || scheduling.HostIPC
+		}
+		// Subcommands only fill their field when it is set
+		return hasSchedulingFields(item)
+	}
+	return true
+}
+
+// nestByNamespace groups the output items by namespace, keeping their order within each namespace
+func nestByNamespace(output Output) NamespacedOutput {
+	nested := NamespacedOutput{Namespaces: make(map[string]Output)}
+	for _, item := range output.Items {
+		group := nested.Namespaces[item.Namespace]
+		group.Items = append(group.Items, item)
+		nested.Namespaces[item.Namespace] = group
+	}
+	return nested
+}
+
+// redactedValue replaces the values of annotations matched by --redact
+// Plain letters, so JSON output shows it as is instead of escaping < and > as \u003c and \u003e
+const redactedValue = "REDACTED"
+
+// matchesKeyPattern checks if an annotation or label key matches a pattern:
+// "*" matches any characters (including "/"), a trailing "/" matches every key with that prefix,
+// anything else must match the key exactly
+func matchesKeyPattern(key, pattern string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(key, pattern)
+	}
+	if !strings.Contains(pattern, "*") {
+		return key == pattern
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	matched, _ := regexp.MatchString("^"+strings.Join(parts, ".*")+"$", key)
+	return matched
+}
+
+// redactAnnotations returns a copy of the annotations where the values of matching keys are redacted
+func redactAnnotations(annotations map[string]string, patterns []string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+
+	redacted := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		redacted[key] = value
+		for _, pattern := range patterns {
+			if matchesKeyPattern(key, pattern) {
+				redacted[key] = redactedValue
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+// annotationValueFilter keeps the resources whose annotation value matches a regexp (--annotation-value-regex)
+type annotationValueFilter struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+// parseAnnotationValueFilter parses <key>=<pattern>, the pattern is compiled once for all the resources
+// The key is everything before the first "=", annotation keys can't contain one but patterns may
+func parseAnnotationValueFilter(value string) (*annotationValueFilter, error) {
+	key, pattern, found := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return nil, fmt.Errorf("--annotation-value-regex must be <key>=<pattern>, got '%s'", value)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --annotation-value-regex pattern '%s': %v", pattern, err)
+	}
+	return &annotationValueFilter{key: key, pattern: re}, nil
+}
+
+// matches checks if the annotation is set and its value matches the pattern, unanchored like grep (use ^ for a prefix)
+func (f *annotationValueFilter) matches(annotations map[string]string) bool {
+	value, ok := annotations[f.key]
+	return ok && f.pattern.MatchString(value)
+}
+
+// systemNamespaces are the namespaces excluded by --no-system
+var systemNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// splitNamespaces parses the value of -n, a namespace or a comma-separated list of them (-n staging,prod)
+// Empty entries and repeated namespaces are dropped
+func splitNamespaces(value string) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+// excludeNamespaces drops the items that belong to one of the given namespaces
+func excludeNamespaces(items []unstructured.Unstructured, namespaces []string) []unstructured.Unstructured {
+	excluded := make(map[string]bool)
+	for _, ns := range namespaces {
+		excluded[ns] = true
+	}
+
+	var kept []unstructured.Unstructured
+	for _, item := range items {
+		if !excluded[item.GetNamespace()] {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// dedupeOwners collapses identical owners (namespace/kind/name) and counts how many resources share each one
+// Resources without owners are counted under <none>, owners are kept in order of first appearance
+func dedupeOwners(items []OutputItem) []OwnerCount {
+	var owners []OwnerCount
+	index := make(map[string]int)
+
+	add := func(owner OwnerCount) {
+		key := owner.Namespace + "/" + owner.Kind + "/" + owner.Name
+		if i, ok := index[key]; ok {
+			owners[i].Count++
+			return
+		}
+		owner.Count = 1
+		index[key] = len(owners)
+		owners = append(owners, owner)
+	}
+
+	for _, item := range items {
+		if len(item.OwnerReferences) == 0 {
+			add(OwnerCount{Namespace: item.Namespace, Kind: "<none>", Name: "<none>"})
+			continue
+		}
+		for _, ownerRef := range item.OwnerReferences {
+			add(OwnerCount{
+				Namespace:  ownerRef.Namespace,
+				APIVersion: ownerRef.APIVersion,
+				Kind:       ownerRef.Kind,
+				Name:       ownerRef.Name,
+			})
+		}
+	}
+
+	return owners
+}
+
+// dedupeIdenticalMaps collapses resources whose labels (or annotations) are exactly the same and counts them
+// Resources are grouped by a hash of their serialized map, resources without any count as an empty set.
+// The most shared sets come first, then in order of first appearance.
+func dedupeIdenticalMaps(items []OutputItem, cmdType string) []IdenticalMapCount {
+	sets := []IdenticalMapCount{}
+	index := make(map[string]int)
+	for _, item := range items {
+		values := map[string]string{}
+		if cmdType == "labels" && item.Labels != nil {
+			values = *item.Labels
+		} else if cmdType == "annotations" && item.Annotations != nil {
+			values = *item.Annotations
+		}
+
+		// encoding/json sorts map keys, so identical maps serialize identically
+		serialized, _ := json.Marshal(values)
+		sum := sha256.Sum256(serialized)
+		key := hex.EncodeToString(sum[:])
+		if i, ok := index[key]; ok {
+			sets[i].Count++
+			continue
+		}
+		index[key] = len(sets)
+		sets = append(sets, IdenticalMapCount{Values: values, Count: 1, Example: outputItemKey(item)})
+	}
+
+	sort.SliceStable(sets, func(i, j int) bool {
+		return sets[i].Count > sets[j].Count
+	})
+	return sets
+}
+
+// diffNamespaceLabels compares the label keys used by the resources of two namespaces (--diff-namespace)
+// Keys used in both are common, the others are listed under the namespace that uses them.
+func diffNamespaceLabels(items []OutputItem, namespaces []string) NamespaceLabelDiff {
+	diff := NamespaceLabelDiff{
+		Namespaces: namespaces,
+		Resources:  make([]int, len(namespaces)),
+		Common:     []LabelKeyUsage{},
+		OnlyIn:     make(map[string][]LabelKeyUsage),
+	}
+	position := make(map[string]int)
+	for i, namespace := range namespaces {
+		position[namespace] = i
+		diff.OnlyIn[namespace] = []LabelKeyUsage{}
+	}
+
+	counts := make(map[string][]int)
+	for _, item := range items {
+		i, ok := position[item.Namespace]
+		if !ok {
+			continue
+		}
+		diff.Resources[i]++
+		if item.Labels == nil {
+			continue
+		}
+		for key := range *item.Labels {
+			if counts[key] == nil {
+				counts[key] = make([]int, len(namespaces))
+			}
+			counts[key][i]++
+		}
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		usage := LabelKeyUsage{Key: key, Counts: counts[key]}
+		var usedIn []string
+		for i, count := range usage.Counts {
+			if count > 0 {
+				usedIn = append(usedIn, namespaces[i])
+			}
+		}
+		if len(usedIn) == len(namespaces) {
+			diff.Common = append(diff.Common, usage)
+		} else {
+			diff.OnlyIn[usedIn[0]] = append(diff.OnlyIn[usedIn[0]], usage)
+		}
+	}
+	return diff
+}
+
+// countLabelValues tallies the values a label takes across resources
+// Resources without the label are counted apart, they are not a value of the label.
+func countLabelValues(items []OutputItem, labelKey string) LabelValueCounts {
+	counts := make(map[string]int)
+	result := LabelValueCounts{Label: labelKey}
+	for _, item := range items {
+		value, ok := "", false
+		if item.Labels != nil {
+			value, ok = (*item.Labels)[labelKey]
+		}
+		if !ok {
+			result.Missing++
 			continue
 		}
+		counts[value]++
+	}
 
-		// Check if it's a short string flag with attached value (e.g., -oyaml)
-		expanded := false
-		for flag := range stringFlags {
-			if strings.HasPrefix(arg, flag) && len(arg) > len(flag) {
-				// Check if the character after flag is not '=' (handled by flag package)
-				if arg[len(flag)] != '=' {
-					// Split: -oyaml -> -o yaml
-					result = append(result, flag, arg[len(flag):])
-					expanded = true
-					break
-				}
-			}
-		}
+	result.Values = make([]LabelValueCount, 0, len(counts))
+	for value, count := range counts {
+		result.Values = append(result.Values, LabelValueCount{Value: value, Count: count})
+	}
+	result.Distinct = len(result.Values)
 
-		if expanded {
-			continue
+	// Most common values first, then by value for consistent output
+	sort.Slice(result.Values, func(i, j int) bool {
+		if result.Values[i].Count != result.Values[j].Count {
+			return result.Values[i].Count > result.Values[j].Count
 		}
+		return result.Values[i].Value < result.Values[j].Value
+	})
 
-		// Check for combined boolean flags (e.g., -Ac -> -A -c)
-		if len(arg) > 2 {
-			allBool := true
-			for i := 1; i < len(arg); i++ {
-				shortFlag := "-" + string(arg[i])
-				if !boolFlags[shortFlag] {
-					allBool = false
-					break
-				}
+	return result
+}
+
+// countByOwnerKind tallies how many resources are owned by each owner kind
+// Only the controller owner of a resource is counted, falling back to its first owner reference.
+// Resources without owners are counted as <none>, mirror pods of static pods (owned by a Node) as Node (static).
+func countByOwnerKind(items []OutputItem) []OwnerKindCount {
+	counts := make(map[string]int)
+	for _, item := range items {
+		kind := "<none>"
+		if len(item.OwnerReferences) > 0 {
+			// The controller is what manages the resource, other owners (e.g. a ConfigMap) only hold it
+			owner := controllerOwner(item.OwnerReferences)
+			if owner == nil {
+				owner = &item.OwnerReferences[0]
 			}
-			if allBool {
-				for i := 1; i < len(arg); i++ {
-					result = append(result, "-"+string(arg[i]))
-				}
-				continue
+			kind = owner.Kind
+			if kind == "Node" {
+				kind = "Node (static)"
 			}
 		}
+		counts[kind]++
+	}
 
-		// No expansion needed
-		result = append(result, arg)
+	result := make([]OwnerKindCount, 0, len(counts))
+	for kind, count := range counts {
+		result = append(result, OwnerKindCount{Kind: kind, Count: count})
 	}
 
+	// Most common kinds first, then by name for consistent output
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Kind < result[j].Kind
+	})
+
 	return result
 }
 
@@ -126,11 +1137,51 @@ func main() {
 	// Parse command type
 	cmdType := os.Args[1]
 
+	// Handle snapshot-diff command
+	if cmdType == "snapshot-diff" {
+		handleSnapshotDiff(os.Args[2:])
+		os.Exit(0)
+	}
+
+	// Handle snapshot command: run the wrapped command and save its output to a file
+	snapshotMode := false
+	if cmdType == "snapshot" {
+		if len(os.Args) < 3 || isHelpFlag(os.Args[2]) {
+			printSnapshotUsage()
+			os.Exit(0)
+		}
+		snapshotMode = true
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		cmdType = os.Args[1]
+		if !isCommand(cmdType) && cmdType != "scheduling" {
+			fmt.Fprintf(os.Stderr, "Error: snapshot requires a resource command (labels, annotations, owner, pdb, command, lifecycle, revision, identity, replicas, service, finalizers, network, hooks, volumes, readiness, env, scheduling), got '%s'\n", cmdType)
+			os.Exit(1)
+		}
+	}
+
 	// Handle completion command
 	if cmdType == "completion" {
 		handleCompletion(os.Args[2:])
 		os.Exit(0)
 	}
+
+	// Namespace names for the completion scripts, not listed in the usage
+	if cmdType == "__list-namespaces" {
+		handleListNamespaces(os.Args[2:])
+		os.Exit(0)
+	}
+
+	// Handle resources command: the resource types served by the cluster (not scheduling resources)
+	if cmdType == "resources" {
+		handleAPIResources(os.Args[2:])
+		os.Exit(0)
+	}
+
+	// List timings of the dynamic and metadata-only clients, for maintainers, not listed in the usage
+	if cmdType == "bench" {
+		handleBench(os.Args[2:])
+		os.Exit(0)
+	}
 	var subCommand string
 	var resourceType string
 	var argsOffset int
@@ -169,9 +1220,9 @@ func main() {
 			argsOffset = 3
 		}
 	} else {
-		// Other commands (labels, annotations, owner)
-		if cmdType != "labels" && cmdType != "annotations" && cmdType != "owner" {
-			fmt.Fprintf(os.Stderr, "Error: command type must be 'labels', 'annotations', 'owner', 'scheduling', or 'completion', got '%s'\n", cmdType)
+		// Other commands (labels, annotations, owner, pdb, command, lifecycle, revision, identity, replicas, service, finalizers, network, hooks, volumes, readiness, env)
+		if !isCommand(cmdType) {
+			fmt.Fprintf(os.Stderr, "Error: command type must be 'labels', 'annotations', 'owner', 'pdb', 'command', 'lifecycle', 'revision', 'identity', 'replicas', 'service', 'finalizers', 'network', 'hooks', 'volumes', 'readiness', 'env', 'scheduling', 'resources', 'snapshot', 'snapshot-diff', or 'completion', got '%s'\n", cmdType)
 			printUsage()
 			os.Exit(1)
 		}
@@ -192,30 +1243,160 @@ func main() {
 		argsOffset = 3
 	}
 
+	// The resource type may be omitted when reading objects from a file (-F)
+	if strings.HasPrefix(resourceType, "-") {
+		resourceType = ""
+		argsOffset--
+	}
+
 	// Parse flags
 	var namespace string
 	var allNamespaces bool
 	var selector string
 	var outputFormat string
 	var colorOutput bool
+	var compactAffinityOutput bool
+	var fullGVK bool
+	var snapshotFile string
+	var countByKind bool
+	var countUnique string
+	var diffNamespace bool
+	var dedupe bool
+	var dedupeIdentical bool
+	var verbosity int
+	var sinceRevision bool
+	var filenames repeatedFlag
+	var groupByNamespace bool
+	var fieldSelector string
+	var rawFieldSelector string
+	var asMap bool
+	var nestByNamespaceOutput bool
+	var unwrapSingle bool
+	var withUsage bool
+	var resolvePriority bool
+	var explain bool
+	var tableLayout string
+	var expandRefs bool
+	var managedBy string
+	var annotationValueRegex string
+	var inheritNamespaceLabels bool
+	var managedFieldsSummary bool
+	var jsonPointers repeatedFlag
+	var showSpecPath bool
+	var kubeContext string
+	var tokenFile string
+	var excludedNamespaces string
+	var noSystem bool
+	var redact string
+	var allowMissingTemplate bool
+	var nonEmpty bool
+	var contextPrefix bool
+	var noFallbackNamespace bool
+	var showNamespaceSource bool
+	var wide bool
+	var maxColWidth int
+	var abbrevNamespace bool
+	var excelCompat bool
+	var legend bool
+	var labelColumnsFlag string
+	var fromCache bool
+	var concurrency int
+	var groupByAnnotation string
+	var watchMode bool
+	var watchTimeout time.Duration
+	var strictExitCodes bool
+	var strict bool
+	var scope string
+	var includeUnavailableGroups bool
+	var quiet bool
+	var globNames bool
+
+	// Load user configuration (missing file is fine)
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Determine default output format based on command type
 	defaultFormat := "yaml"
-	if cmdType == "owner" {
+	if cmdType == "owner" || cmdType == "pdb" {
 		defaultFormat = "table"
 	}
+	// Env var and config file override the command default, -o still wins
+	defaultFormat = getDefaultOutputFormat(cmdType, defaultFormat, config)
 
 	fs := flag.NewFlagSet("getinfo", flag.ExitOnError)
-	fs.StringVar(&namespace, "n", "", "namespace")
-	fs.StringVar(&namespace, "namespace", "", "namespace")
+	fs.StringVar(&namespace, "n", "", "namespace, or comma-separated namespaces")
+	fs.StringVar(&namespace, "namespace", "", "namespace, or comma-separated namespaces")
 	fs.BoolVar(&allNamespaces, "A", false, "all-namespaces")
 	fs.BoolVar(&allNamespaces, "all-namespaces", false, "all-namespaces")
 	fs.StringVar(&selector, "l", "", "selector")
 	fs.StringVar(&selector, "selector", "", "selector")
-	fs.StringVar(&outputFormat, "o", defaultFormat, "output format (json, yaml, table)")
-	fs.StringVar(&outputFormat, "output", defaultFormat, "output format (json, yaml, table)")
-	fs.BoolVar(&colorOutput, "c", false, "colorize JSON output")
-	fs.BoolVar(&colorOutput, "color", false, "colorize JSON output")
+	fs.StringVar(&outputFormat, "o", defaultFormat, "output format (json, jsonl, yaml, table, csv, tsv, name, otel, jsonpath=..., go-template=...)")
+	fs.StringVar(&outputFormat, "output", defaultFormat, "output format (json, jsonl, yaml, table, csv, tsv, name, otel, jsonpath=..., go-template=...)")
+	fs.IntVar(&verbosity, "v", 0, "log level for client-go requests (e.g. 6 logs every API call)")
+	fs.IntVar(&verbosity, "verbosity", 0, "log level for client-go requests (e.g. 6 logs every API call)")
+	fs.BoolVar(&colorOutput, "c", config.Color, "colorize JSON and table output")
+	fs.BoolVar(&colorOutput, "color", config.Color, "colorize JSON and table output")
+	fs.BoolVar(&legend, "legend", false, "print a line explaining the colors above colored output (with -c)")
+	fs.BoolVar(&compactAffinityOutput, "compact-affinity", false, "prune empty affinity branches (scheduling only)")
+	fs.BoolVar(&fullGVK, "full-gvk", false, "show owner apiVersion/kind in table output (owner only)")
+	fs.StringVar(&snapshotFile, "snapshot-file", "", "snapshot file to write (snapshot only)")
+	fs.BoolVar(&countByKind, "count-by-kind", false, "count resources per owner kind (owner only)")
+	fs.StringVar(&countUnique, "count-unique", "", "count the distinct values of a label across the resources (labels only)")
+	fs.BoolVar(&diffNamespace, "diff-namespace", false, "compare the label keys used in the two namespaces given with -n (labels only)")
+	fs.BoolVar(&sinceRevision, "since-revision", false, "tell whether Deployment pods belong to the current or a previous revision (owner only)")
+	fs.BoolVar(&dedupe, "dedupe", false, "collapse identical owners and count the resources sharing them (owner only)")
+	fs.BoolVar(&dedupeIdentical, "dedupe-identical", false, "collapse resources with identical labels or annotations and count them (labels and annotations only)")
+	fs.Var(&filenames, "F", "read objects from a file or stdin (-), may be repeated")
+	fs.Var(&filenames, "filename", "read objects from a file or stdin (-), may be repeated")
+	fs.BoolVar(&groupByNamespace, "group-by-namespace", false, "group table rows by namespace")
+	fs.StringVar(&groupByAnnotation, "group-by-annotation", "", "group table rows by the value of an annotation (e.g. owner-team)")
+	fs.BoolVar(&excelCompat, "excel-compat", false, "write a UTF-8 BOM and CRLF line endings (csv and tsv only)")
+	fs.BoolVar(&wide, "wide", false, "expand summarized table cells (e.g. scheduling affinity rules)")
+	fs.IntVar(&maxColWidth, "max-col-width", 0, "shorten table cells longer than this many characters (0 means no limit)")
+	fs.BoolVar(&abbrevNamespace, "abbrev-namespace", false, "shorten namespace prefixes shared by several namespaces in table output")
+	fs.StringVar(&fieldSelector, "field-selector", "", "field selector (e.g., status.phase=Running)")
+	fs.BoolVar(&watchMode, "w", false, "watch for changes and print one event per line (-o jsonl only)")
+	fs.BoolVar(&watchMode, "watch", false, "watch for changes and print one event per line (-o jsonl only)")
+	fs.DurationVar(&watchTimeout, "watch-timeout", 0, "stop watching after this long and exit with 0 (e.g. 2m)")
+	fs.StringVar(&scope, "scope", scopeAll, "resource types included by 'all': namespaced, cluster or all")
+	fs.BoolVar(&includeUnavailableGroups, "include-unavailable-groups", false, "retry API groups that fail discovery before skipping them")
+	fs.BoolVar(&fromCache, "from-cache", false, "list from the API server's watch cache (resourceVersion=0), may be slightly stale")
+	fs.IntVar(&concurrency, "concurrency", config.Concurrency, "number of namespaces queried at once with -n a,b (0 means no limit)")
+	fs.StringVar(&rawFieldSelector, "raw-field-selector", "", "field selector passed verbatim to the API server")
+	fs.BoolVar(&asMap, "as-map", false, "output an object keyed by namespace/name instead of an items array")
+	fs.BoolVar(&nestByNamespaceOutput, "nest-by-namespace", false, "output items nested under their namespace")
+	fs.BoolVar(&unwrapSingle, "unwrap-single", false, "output a single result as the item itself, without the items wrapper")
+	fs.StringVar(&excludedNamespaces, "exclude-namespaces", "", "comma-separated namespaces to leave out (with -A)")
+	fs.BoolVar(&noFallbackNamespace, "no-fallback-namespace", false, "require -n or -A instead of using the kubeconfig namespace")
+	fs.BoolVar(&showNamespaceSource, "show-namespace-source", false, "print the namespace queried and where it came from (-n, kubeconfig context or default) to stderr")
+	fs.BoolVar(&noSystem, "no-system", false, "leave out kube-system, kube-public and kube-node-lease")
+	fs.StringVar(&redact, "redact", "", "comma-separated annotation keys whose values are hidden (prefix/ or glob*, annotations only)")
+	fs.StringVar(&kubeContext, "context", "", "name of the kubeconfig context to use")
+	fs.StringVar(&tokenFile, "token-file", "", "read the bearer token from a file, reloaded when it is rotated")
+	fs.BoolVar(&contextPrefix, "context-prefix", false, "prefix names with the kubeconfig context (-o name only)")
+	fs.StringVar(&managedBy, "managed-by", "", "only resources whose app.kubernetes.io/managed-by label equals the value")
+	fs.StringVar(&annotationValueRegex, "annotation-value-regex", "", "only resources whose annotation value matches a regexp, as <key>=<pattern>")
+	fs.StringVar(&labelColumnsFlag, "L", "", "comma-separated label keys shown as one table column each (labels only)")
+	fs.StringVar(&labelColumnsFlag, "label-columns", "", "comma-separated label keys shown as one table column each (labels only)")
+	fs.BoolVar(&inheritNamespaceLabels, "inherit-namespace-labels", false, "also show the labels of each resource's namespace (labels only)")
+	fs.BoolVar(&managedFieldsSummary, "managed-fields-summary", false, "show which field manager owns which fields")
+	fs.Var(&jsonPointers, "json-pointer", "add the field at a JSON pointer (RFC 6901, e.g. /metadata/annotations/example.com~1owner), may be repeated")
+	fs.BoolVar(&showSpecPath, "show-spec-path", false, "show where the pod spec of each resource was read from (e.g. spec.template.spec)")
+	fs.BoolVar(&strict, "strict", false, "fail on unknown flags and on flags after the resource names instead of reading them as names")
+	fs.BoolVar(&strictExitCodes, "strict-exit-codes", false, "exit with 2 (no results), 3 (not found), 4 (forbidden), 5 (connection error) or 6 (invalid flags) instead of 1")
+	fs.BoolVar(&allowMissingTemplate, "allow-missing-template", false, "silently skip resources without a pod spec (scheduling only)")
+	fs.BoolVar(&nonEmpty, "non-empty", false, "only show resources where the field shown by the command is set (e.g. pods with tolerations)")
+	fs.BoolVar(&withUsage, "with-usage", false, "show actual usage from the metrics API (scheduling resources only)")
+	fs.BoolVar(&resolvePriority, "resolve-priority", false, "look up the PriorityClass for its value and preemption policy (scheduling priority only)")
+	fs.StringVar(&tableLayout, "table-layout", "default", "merged shows each resource as request/limit, e.g. cpu: 500m/1 (scheduling resources only)")
+	fs.BoolVar(&explain, "explain", false, "describe what each scheduling field means below the output (scheduling only)")
+	fs.BoolVar(&expandRefs, "expand-refs", false, "list the variables of the ConfigMaps and Secrets referenced by envFrom (env only)")
+	fs.BoolVar(&globNames, "glob", false, "treat resource names with *, ? or [ as patterns matched against a list of the resources")
+	fs.BoolVar(&quiet, "q", false, "only print the data and errors, no warnings or notes on stderr")
+	fs.BoolVar(&quiet, "quiet", false, "only print the data and errors, no warnings or notes on stderr")
 
 	// Parse remaining arguments (resource names and flags)
 	args := os.Args[argsOffset:]
@@ -223,36 +1404,139 @@ func main() {
 	args = preprocessArgs(args)
 	fs.Parse(args)
 
-	// Get resource names (non-flag arguments after parsing)
-	resourceNames := fs.Args()
+	// Request-level logs of client-go go through klog, silent unless -v is given
+	if verbosity > 0 {
+		if err := klogFlags.Set("v", strconv.Itoa(verbosity)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid verbosity %d: %v\n", verbosity, err)
+			os.Exit(1)
+		}
+	}
 
-	// Get kubeconfig
-	config, err := getKubeconfig()
+	// Get resource names (non-flag arguments after parsing)
+	positional := fs.Args()
+	for i, arg := range positional {
+		if arg == strictFlag {
+			strict = true
+			positional = append(positional[:i:i], positional[i+1:]...)
+			break
+		}
+	}
+	if strict {
+		if err := checkStrictArgs(fs, positional); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(errorExitCode(err, strictExitCodes))
+		}
+	}
+	// Names pasted from other output often carry stray whitespace
+	resourceNames, err := trimResourceNames(positional)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting kubeconfig: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(config)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating dynamic client: %v\n", err)
-		os.Exit(1)
+	// With --glob, names like web-* are matched against a list of the resources instead of fetched one by one
+	namesToGet := resourceNames
+	globbing := globNames && hasGlobPattern(resourceNames)
+	if globbing {
+		if err := validateGlobPatterns(resourceNames); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		namesToGet = nil
 	}
 
-	// Get GVR (GroupVersionResource) for the resource type
-	gvr, namespaced, err := getGVR(resourceType, config)
-	if err != nil {
+	// Output format combinations are checked before anything is asked from the API server
+	format, _ := splitOutputTemplate(outputFormat)
+
+	// Config file defaults that only make sense for some queries, a flag on the command line still wins
+	if !flagPassed(fs, "max-col-width") && format == "table" {
+		maxColWidth = config.MaxColWidth
+	}
+	if !flagPassed(fs, "exclude-namespaces") && allNamespaces {
+		excludedNamespaces = strings.Join(config.ExcludeNamespaces, ",")
+	}
+	var labelColumns []string
+	for _, key := range strings.Split(labelColumnsFlag, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			labelColumns = append(labelColumns, key)
+		}
+	}
+	var redactPatterns []string
+	for _, pattern := range strings.Split(redact, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			redactPatterns = append(redactPatterns, pattern)
+		}
+	}
+	flags := outputFlags{
+		cmdType:              cmdType,
+		format:               format,
+		snapshot:             snapshotMode,
+		snapshotFile:         snapshotFile,
+		watch:                watchMode,
+		watchTimeout:         watchTimeout,
+		countByKind:          countByKind,
+		countUnique:          countUnique,
+		diffNamespace:        diffNamespace,
+		dedupe:               dedupe,
+		dedupeIdentical:      dedupeIdentical,
+		asMap:                asMap,
+		nestByNamespace:      nestByNamespaceOutput,
+		unwrapSingle:         unwrapSingle,
+		managedFieldsSummary: managedFieldsSummary,
+		jsonPointers:         jsonPointers,
+		showSpecPath:         showSpecPath,
+		contextPrefix:        contextPrefix,
+		fullGVK:              fullGVK,
+		wide:                 wide,
+		tableLayout:          tableLayout,
+		maxColWidth:          maxColWidth,
+		abbrevNamespace:      abbrevNamespace,
+		explain:              explain,
+		groupByNamespace:     groupByNamespace,
+		groupByAnnotation:    groupByAnnotation,
+		excelCompat:          excelCompat,
+		color:                colorOutput,
+		legend:               legend,
+		labelColumns:         labelColumns,
+
+		subCommand:             subCommand,
+		resourceType:           resourceType,
+		fromFile:               len(filenames) > 0,
+		namespace:              namespace,
+		allNamespaces:          allNamespaces,
+		scope:                  scope,
+		fieldSelector:          fieldSelector != "" || rawFieldSelector != "",
+		resourceNames:          len(namesToGet) > 0,
+		sinceRevision:          sinceRevision,
+		fromCache:              fromCache,
+		concurrency:            concurrency,
+		context:                kubeContext != "",
+		tokenFile:              tokenFile != "",
+		includeUnavailable:     includeUnavailableGroups,
+		withUsage:              withUsage,
+		resolvePriority:        resolvePriority,
+		redact:                 len(redactPatterns) > 0,
+		inheritNamespaceLabels: inheritNamespaceLabels,
+		expandRefs:             expandRefs,
+	}
+	if err := validateFlags(flags); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(errorExitCode(err, strictExitCodes))
+	}
+
+	// A resource type or files are needed, validateFlags rejects both at once
+	if len(filenames) == 0 && resourceType == "" {
+		fmt.Fprintf(os.Stderr, "Error: resource type is required\n")
 		os.Exit(1)
 	}
 
-	// Determine namespace
-	if allNamespaces {
-		namespace = ""
-	} else if namespace == "" && namespaced {
-		// Try to get namespace from kubeconfig context
-		namespace = getCurrentNamespace()
+	var annotationFilter *annotationValueFilter
+	if annotationValueRegex != "" {
+		annotationFilter, err = parseAnnotationValueFilter(annotationValueRegex)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Parse label selector
@@ -265,91 +1549,603 @@ func main() {
 		}
 	}
 
-	// Get resources
-	items, err := getResources(dynamicClient, gvr, namespaced, namespace, resourceNames, labelSelector)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error getting resources: %v\n", err)
-		os.Exit(1)
+	// --managed-by is shorthand for -l app.kubernetes.io/managed-by=<value>, combined with -l if both are given
+	if managedBy != "" {
+		requirement, err := labels.NewRequirement(managedByLabel, selection.Equals, []string{managedBy})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --managed-by value: %v\n", err)
+			os.Exit(1)
+		}
+		if labelSelector == nil {
+			labelSelector = labels.NewSelector()
+		}
+		labelSelector = labelSelector.Add(*requirement)
+	}
+
+	// Validate the field selector syntax, the raw field selector takes precedence and is passed verbatim
+	if fieldSelector != "" {
+		parsedFieldSelector, err := fields.ParseSelector(fieldSelector)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing field selector: %v\n", err)
+			os.Exit(1)
+		}
+		fieldSelector = parsedFieldSelector.String()
+	}
+	if rawFieldSelector != "" {
+		fieldSelector = rawFieldSelector
+	}
+
+	var dynamicClient dynamic.Interface
+	var gvr schema.GroupVersionResource
+	var namespaced bool
+	var items []unstructured.Unstructured
+
+	if len(filenames) > 0 {
+		// Read objects from a file or stdin instead of querying the API
+		items, err = readObjectsFromFiles(filenames)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if allNamespaces {
+			namespace = ""
+		}
+		items = filterObjects(items, splitNamespaces(namespace), namesToGet, labelSelector)
+		if globbing {
+			items = filterByNamePatterns(items, resourceNames)
+		}
+		namespaced = hasNamespacedObjects(items)
+		if err := validateNamespacedFlags(flags, namespaced); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(errorExitCode(err, strictExitCodes))
+		}
+	} else {
+		// Get kubeconfig
+		restConfig, err := getKubeconfig(kubeContext, tokenFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting kubeconfig: %v\n", err)
+			os.Exit(1)
+		}
+		// The API server's own warnings (e.g. deprecated versions) are printed by client-go
+		if quiet {
+			restConfig.WarningHandler = rest.NoWarnings{}
+		}
+
+		// Create dynamic client
+		dynamicClient, err = dynamic.NewForConfig(restConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating dynamic client: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Resolve the resource type(s) to GroupVersionResources
+		resources, failedGroups, err := resolveResourceTypes(resourceType, scope, restConfig, includeUnavailableGroups)
+		if err != nil {
+			// "scheduling priority web-1" reads priority as the subcommand, explain when it was meant as a resource type
+			if cmdType == "scheduling" && subCommand != "" {
+				if shadowed, ok := resolveShadowedSubcommand(subCommand, restConfig, includeUnavailableGroups); ok {
+					fullName := shadowed.GVR.GroupResource().String()
+					fmt.Fprintf(os.Stderr, "Error: '%s' is read as the 'scheduling %s' subcommand, so '%s' was taken as the resource type and isn't one. "+
+						"'%s' is also the resource type %s, use its full name to query it: kubectl getinfo scheduling %s ...\n",
+						subCommand, subCommand, resourceType, subCommand, fullName, fullName)
+					os.Exit(errorExitCode(err, strictExitCodes))
+				}
+			}
+			printErrors("Error", err)
+			os.Exit(errorExitCode(err, strictExitCodes))
+		}
+		if len(failedGroups) > 0 && !quiet {
+			fmt.Fprintf(os.Stderr, "Warning: discovery failed for %s, their resources are skipped\n", strings.Join(failedGroups, ", "))
+		}
+		if len(resources) > 1 {
+			if len(namesToGet) > 0 {
+				fmt.Fprintf(os.Stderr, "Error: resource names cannot be combined with several resource types, unless they are --glob patterns\n")
+				os.Exit(1)
+			}
+			if watchMode {
+				fmt.Fprintf(os.Stderr, "Error: --watch supports a single resource type\n")
+				os.Exit(1)
+			}
+		}
+
+		for _, resource := range resources {
+			// PodDisruptionBudgets only select pods in their own namespace
+			if cmdType == "pdb" && !resource.Namespaced {
+				fmt.Fprintf(os.Stderr, "Error: 'pdb' command only supports namespaced resources, '%s' is cluster-scoped\n", resource.GVR.Resource)
+				os.Exit(1)
+			}
+			// The namespace column is shown as soon as one of the types is namespaced
+			namespaced = namespaced || resource.Namespaced
+		}
+		if err := validateNamespacedFlags(flags, namespaced); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(errorExitCode(err, strictExitCodes))
+		}
+		if len(resources) > 0 {
+			gvr = resources[0].GVR
+		}
+
+		// Determine namespace
+		// The source is reported on request, "why am I seeing the default namespace" is a common question
+		var namespaceSource string
+		namespace, namespaceSource, err = targetNamespace(namespace, allNamespaces, namespaced, noFallbackNamespace, kubeContext)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if showNamespaceSource || verbosity >= 1 {
+			switch {
+			case !namespaced:
+				fmt.Fprintf(os.Stderr, "Namespace: none, cluster-scoped resources\n")
+			case namespace == "":
+				fmt.Fprintf(os.Stderr, "Namespace: all (%s)\n", namespaceSource)
+			default:
+				fmt.Fprintf(os.Stderr, "Namespace: %s (%s)\n", namespace, namespaceSource)
+			}
+		}
+
+		// The watch streams the objects itself, nothing is listed up front
+		if !watchMode {
+			// Commands that only read metadata fetch PartialObjectMetadata instead of full objects
+			var client resourceClient = dynamicResourceClient{client: dynamicClient}
+			if needsOnlyMetadata(cmdType) && pointsIntoMetadata(jsonPointers) {
+				metadataClient, err := metadata.NewForConfig(restConfig)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error creating metadata client: %v\n", err)
+					os.Exit(1)
+				}
+				client = metadataResourceClient{client: metadataClient}
+			}
+
+			// Get resources, type by type
+			// The errors of all types are collected so a single run reports every failure
+			var forbiddenTypes, failedTypes []string
+			var errs []error
+			for _, resource := range resources {
+				resourceItems, deniedNamespaces, err := getResourcesInNamespaces(client, resource.GVR, resource.Namespaced, splitNamespaces(namespace), namesToGet, labelSelector, fieldSelector, fromCache, concurrency)
+				if err != nil {
+					// A scan of all types skips the ones that aren't readable instead of failing
+					if resourceType == allResourceTypes && isSkippableListError(err) {
+						if errors.Is(err, errForbidden) {
+							forbiddenTypes = append(forbiddenTypes, resource.GVR.GroupResource().String())
+						} else {
+							failedTypes = append(failedTypes, fmt.Sprintf("%s (%s)", resource.GVR.GroupResource(), listErrorMessage(err)))
+						}
+						continue
+					}
+					errs = append(errs, err)
+					continue
+				}
+				if globbing {
+					resourceItems = filterByNamePatterns(resourceItems, resourceNames)
+				}
+				if len(deniedNamespaces) > 0 && !quiet {
+					fmt.Fprintf(os.Stderr, "Warning: listing %s is forbidden in %d namespace(s), showing accessible namespaces only. Denied: %s\n",
+						resource.GVR.Resource, len(deniedNamespaces), strings.Join(deniedNamespaces, ", "))
+				}
+
+				// Metadata-only objects don't carry their type
+				for i := range resourceItems {
+					if resourceItems[i].GetKind() == "" {
+						resourceItems[i].SetAPIVersion(resource.GVR.GroupVersion().String())
+						resourceItems[i].SetKind(resource.Kind)
+					}
+				}
+				items = append(items, resourceItems...)
+			}
+			if len(forbiddenTypes) > 0 && !quiet {
+				fmt.Fprintf(os.Stderr, "Warning: listing is forbidden for %d resource type(s), they are skipped: %s\n",
+					len(forbiddenTypes), strings.Join(forbiddenTypes, ", "))
+			}
+			if len(failedTypes) > 0 && !quiet {
+				fmt.Fprintf(os.Stderr, "Warning: listing failed for %d resource type(s), they are skipped: %s\n",
+					len(failedTypes), strings.Join(failedTypes, ", "))
+			}
+			if len(errs) > 0 {
+				err := utilerrors.NewAggregate(errs)
+				printErrors("Error getting resources", err)
+				os.Exit(errorExitCode(err, strictExitCodes))
+			}
+		}
+	}
+
+	// Drop excluded namespaces after listing, mostly useful with -A
+	var namespacesToExclude []string
+	for _, ns := range strings.Split(excludedNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespacesToExclude = append(namespacesToExclude, ns)
+		}
+	}
+	if noSystem {
+		namespacesToExclude = append(namespacesToExclude, systemNamespaces...)
+	}
+	if len(namespacesToExclude) > 0 {
+		items = excludeNamespaces(items, namespacesToExclude)
+	}
+
+	// Fetch actual usage, clusters without metrics-server just don't get usage
+	var podUsage map[string]map[string]map[string]interface{}
+	if withUsage {
+		// Several namespaces (-n a,b) are asked one by one, the usage is keyed by namespace/name
+		metricsNamespaces := splitNamespaces(namespace)
+		if len(metricsNamespaces) == 0 {
+			metricsNamespaces = []string{""}
+		}
+		podUsage = make(map[string]map[string]map[string]interface{})
+		for _, metricsNamespace := range metricsNamespaces {
+			namespaceUsage, err := getPodMetrics(dynamicClient, metricsNamespace)
+			if err != nil {
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "Warning: %v. Usage is omitted.\n", err)
+				}
+				break
+			}
+			for key, usage := range namespaceUsage {
+				podUsage[key] = usage
+			}
+		}
 	}
 
 	// Extract labels, annotations, or ownerReferences
 	output := Output{Items: []OutputItem{}}
-	for _, item := range items {
+	// Kinds skipped by scheduling because they have no pod spec, reported once per kind
+	kindsWithoutPodSpec := make(map[string]bool)
+	// PodDisruptionBudgets are listed once per namespace
+	pdbCache := make(map[string][]unstructured.Unstructured)
+	// ReplicaSets and Deployments are fetched once for --since-revision
+	var rollouts *rolloutResolver
+	if sinceRevision {
+		rollouts = newRolloutResolver(dynamicClient)
+	}
+	// Namespace labels are fetched once per namespace
+	namespaceLabelsCache := make(map[string]map[string]string)
+	// ConfigMaps and Secrets referenced by envFrom are fetched once for --expand-refs
+	var envSources *envSourceResolver
+	if expandRefs {
+		envSources = newEnvSourceResolver(dynamicClient)
+	}
+	// PriorityClasses are fetched once for --resolve-priority
+	var priorities *priorityClassResolver
+	if resolvePriority {
+		priorities = newPriorityClassResolver(dynamicClient)
+	}
+	// extractItem turns a resource into its output item, false means the resource is skipped
+	extractItem := func(item unstructured.Unstructured) (OutputItem, bool) {
 		outputItem := OutputItem{
-			Name: item.GetName(),
+			Name:              item.GetName(),
+			ResourceType:      resourceTypeName(item),
+			CreationTimestamp: item.GetCreationTimestamp().Time,
+		}
+
+		// Matched client-side, label selectors have no equivalent for annotations
+		if annotationFilter != nil && !annotationFilter.matches(item.GetAnnotations()) {
+			return outputItem, false
+		}
+
+		// Teams often record ownership in annotations rather than labels
+		if groupByAnnotation != "" {
+			outputItem.AnnotationGroup = "<none>"
+			if value, ok := item.GetAnnotations()[groupByAnnotation]; ok {
+				outputItem.AnnotationGroup = value
+			}
 		}
 
 		if namespaced {
 			outputItem.Namespace = item.GetNamespace()
 		}
 
+		// The path the extractors read the pod spec from, to debug kinds that come out empty
+		if showSpecPath {
+			outputItem.SpecPath = strings.Join(getPodSpecPath(item), ".")
+		}
+
+		// Static pods have no controller, flag them so they aren't mistaken for forgotten unowned pods
+		if cmdType == "labels" || cmdType == "annotations" || cmdType == "owner" {
+			outputItem.Static = isStaticPod(item)
+		}
+
+		// The registered extractor fills the command's fields, what needs other objects or flags is added below
+		extract, _ := lookupExtractor(cmdType, subCommand)
+		extract(item, &outputItem)
+
+		// The lookups below get their own err, the closure must not overwrite main's
+		var err error
 		switch cmdType {
 		case "labels":
-			labels := item.GetLabels()
-			outputItem.Labels = &labels
+			if inheritNamespaceLabels && item.GetNamespace() != "" {
+				namespaceLabels, ok := namespaceLabelsCache[item.GetNamespace()]
+				if !ok {
+					namespaceLabels, err = getNamespaceLabels(dynamicClient, item.GetNamespace())
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Error getting namespace labels: %v\n", err)
+						os.Exit(errorExitCode(err, strictExitCodes))
+					}
+					namespaceLabelsCache[item.GetNamespace()] = namespaceLabels
+				}
+				outputItem.NamespaceLabels = namespaceLabels
+			}
 		case "annotations":
-			annotations := item.GetAnnotations()
-			outputItem.Annotations = &annotations
+			// Hide sensitive values (tokens, config) before anything is printed or saved
+			if len(redactPatterns) > 0 {
+				annotations := redactAnnotations(*outputItem.Annotations, redactPatterns)
+				outputItem.Annotations = &annotations
+			}
 		case "owner":
-			ownerRefs := extractOwnerReferences(item)
-			outputItem.OwnerReferences = ownerRefs
-			// Don't fill labels and annotations when the command is owner
+			if sinceRevision {
+				for _, ownerRef := range outputItem.OwnerReferences {
+					if ownerRef.Kind != "ReplicaSet" {
+						continue
+					}
+					outputItem.Rollout, err = rollouts.resolve(ownerRef.Namespace, ownerRef.Name)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Error resolving rollout revision: %v\n", err)
+						os.Exit(errorExitCode(err, strictExitCodes))
+					}
+					break
+				}
+			}
+		case "pdb":
+			pdbs, ok := pdbCache[item.GetNamespace()]
+			if !ok {
+				pdbs, err = getPodDisruptionBudgets(dynamicClient, item.GetNamespace())
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error getting PodDisruptionBudgets: %v\n", err)
+					os.Exit(errorExitCode(err, strictExitCodes))
+				}
+				pdbCache[item.GetNamespace()] = pdbs
+			}
+			outputItem.PodDisruptionBudgets = extractPodDisruptionBudgets(item, pdbs)
+		case "env":
+			if expandRefs {
+				if err := envSources.expand(item.GetNamespace(), outputItem.Env); err != nil {
+					fmt.Fprintf(os.Stderr, "Error resolving envFrom references: %v\n", err)
+					os.Exit(errorExitCode(err, strictExitCodes))
+				}
+			}
 		case "scheduling":
-			if subCommand == "" {
-				// Show all scheduling info
-				schedulingInfo := extractSchedulingInfo(item)
-				outputItem.Scheduling = schedulingInfo
-			} else {
-				// Show only the specific subcommand field
-				extractSchedulingSubcommand(item, &outputItem, subCommand)
+			// A Service, ConfigMap, etc. has no pod spec: skip it instead of printing an empty item
+			if !hasPodSpec(item) && !hasSchedulingFields(outputItem) {
+				if !allowMissingTemplate && !quiet && !kindsWithoutPodSpec[item.GetKind()] {
+					kindsWithoutPodSpec[item.GetKind()] = true
+					fmt.Fprintf(os.Stderr, "Note: %s has no schedulable pod spec, skipping it. Use --allow-missing-template to skip silently.\n", item.GetKind())
+					if showSpecPath {
+						fmt.Fprintf(os.Stderr, "Note: %s is not a known workload kind, its pod spec was looked up at %s\n", item.GetKind(), outputItem.SpecPath)
+					}
+				}
+				return outputItem, false
+			}
+
+			// Show usage next to requests and limits
+			if usageByContainer, ok := podUsage[item.GetNamespace()+"/"+item.GetName()]; ok {
+				for i := range outputItem.Resources {
+					outputItem.Resources[i].Usage = usageByContainer[outputItem.Resources[i].Name]
+				}
+			}
+
+			// Templates only name the class, its value is what the pods will get
+			if resolvePriority {
+				className, _ := outputItem.Priority["priorityClassName"].(string)
+				priorityClass, err := priorities.resolve(className)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error resolving PriorityClass: %v\n", err)
+					os.Exit(errorExitCode(err, strictExitCodes))
+				}
+				outputItem.PriorityClass = priorityClass
+			}
+
+			// Drop empty affinity branches so only populated rules are shown
+			if compactAffinityOutput {
+				if outputItem.Scheduling != nil {
+					outputItem.Scheduling.Affinity = compactAffinity(outputItem.Scheduling.Affinity)
+				}
+				outputItem.Affinity = compactAffinity(outputItem.Affinity)
 			}
 		}
 
-		output.Items = append(output.Items, outputItem)
-	}
+		// Leave out resources without the attribute being investigated
+		if nonEmpty && !hasNonEmptyResult(outputItem, cmdType) {
+			return outputItem, false
+		}
 
-	// Output in requested format
-	outputFormat = strings.ToLower(outputFormat)
+		// Summarize server-side apply field ownership next to the command's fields
+		if managedFieldsSummary {
+			outputItem.ManagedFields = summarizeManagedFields(item)
+		}
 
-	// Validate table format is only for owner command
-	if outputFormat == "table" && cmdType != "owner" {
-		fmt.Fprintf(os.Stderr, "Error: table format is only supported for 'owner' command. Supported formats: json, yaml\n")
-		os.Exit(1)
+		// Fields asked for by JSON pointer, those the resource doesn't have are left out
+		for _, pointer := range jsonPointers {
+			tokens, _ := parseJSONPointer(pointer)
+			if value, ok := resolveJSONPointer(item.Object, tokens); ok {
+				if outputItem.Fields == nil {
+					outputItem.Fields = make(map[string]interface{})
+				}
+				outputItem.Fields[pointer] = value
+			}
+		}
+
+		return outputItem, true
 	}
 
-	switch outputFormat {
-	case "json":
-		jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	// Stream changes instead of printing the current state once
+	if watchMode {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		// A time-boxed watch ends like an interrupted one, without an error
+		if watchTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, watchTimeout)
+			defer cancel()
+		}
+		err := watchResources(ctx, dynamicClient, gvr, namespace, watchListOptions(labelSelector, fieldSelector), func(eventType watch.EventType, item unstructured.Unstructured) error {
+			if !matchesWatchFilters(item, namesToGet, namespacesToExclude) {
+				return nil
+			}
+			if globbing && !matchesNamePatterns(item.GetName(), resourceNames) {
+				return nil
+			}
+			outputItem, ok := extractItem(item)
+			if !ok {
+				return nil
+			}
+			return printWatchEvent(os.Stdout, eventType, outputItem)
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error watching resources: %v\n", err)
+			os.Exit(errorExitCode(err, strictExitCodes))
 		}
-		if colorOutput {
-			coloredOutput := colorizeJSON(string(jsonOutput))
-			fmt.Print(coloredOutput)
-		} else {
-			fmt.Println(string(jsonOutput))
+		return
+	}
+
+	for _, item := range items {
+		if outputItem, ok := extractItem(item); ok {
+			output.Items = append(output.Items, outputItem)
 		}
-	case "yaml":
-		yamlOutput, err := yaml.Marshal(output)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+	}
+
+	// Empty results get a distinct exit code, returned once the (empty) output is written
+	exitCode := 0
+	if strictExitCodes && len(output.Items) == 0 {
+		exitCode = exitNoResults
+	}
+
+	// Key of the colors on stderr above the output, so the output itself stays parseable. Tables are only colored on a terminal
+	if !snapshotMode && legend && (strings.ToLower(outputFormat) != "table" || term.IsTerminal(int(os.Stdout.Fd()))) {
+		printColorLegend(os.Stderr, strings.ToLower(outputFormat))
+	}
+
+	switch {
+	case snapshotMode:
+		// Save the output to a file instead of printing it
+		snapshot := newSnapshot(cmdType, subCommand, resourceType, output)
+		if snapshotFile == "" {
+			snapshotFile = defaultSnapshotFileName(snapshot.Timestamp)
+		}
+		if err := writeSnapshot(snapshotFile, snapshot); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Print(string(yamlOutput))
-	case "table":
-		printTable(output, cmdType, subCommand, namespaced)
+		fmt.Printf("Snapshot of %d item(s) written to %s\n", len(output.Items), snapshotFile)
+	case countByKind:
+		// Replace the per-resource output with a tally of owner kinds
+		printOwnerKindCounts(countByOwnerKind(output.Items), strings.ToLower(outputFormat), colorOutput)
+	case countUnique != "":
+		// Replace the per-resource output with the distinct values of a label
+		printLabelValueCounts(countLabelValues(output.Items, countUnique), strings.ToLower(outputFormat), colorOutput)
+	case diffNamespace:
+		// Replace the per-resource output with a comparison of the label keys of two namespaces
+		printNamespaceLabelDiff(diffNamespaceLabels(output.Items, splitNamespaces(namespace)), strings.ToLower(outputFormat), colorOutput)
+	case dedupeIdentical:
+		// Replace the per-resource output with one row per distinct set of labels or annotations
+		printIdenticalMapCounts(dedupeIdenticalMaps(output.Items, cmdType), cmdType, strings.ToLower(outputFormat), colorOutput)
+	case dedupe:
+		// Replace the per-resource output with one row per distinct owner
+		printOwnerCounts(dedupeOwners(output.Items), strings.ToLower(outputFormat), colorOutput, namespaced)
 	default:
-		if cmdType == "owner" {
-			fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml\n", outputFormat)
+		// Output in requested format, templates (jsonpath=..., go-template=...) keep their case
+		outputFormat, outputTemplate := splitOutputTemplate(outputFormat)
+
+		// The context name comes from the kubeconfig, objects read from a file have none unless --context is given
+		var namePrefix string
+		if contextPrefix {
+			contextName, err := getContextName(kubeContext)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			namePrefix = contextName + "/"
+		}
+
+		// Alternate shapes: object keyed by namespace/name, items nested under their namespace, or a lone item
+		// Only the items list and the namespaces object carry the schema version, the other shapes have no room for it
+		if outputFormat == "json" || outputFormat == "yaml" {
+			output.SchemaVersion = outputSchemaVersion
+		}
+		var data interface{} = output
+		if asMap {
+			data = outputAsMap(output)
+		} else if nestByNamespaceOutput {
+			nested := nestByNamespace(output)
+			nested.SchemaVersion = output.SchemaVersion
+			data = nested
+		} else if unwrapSingle && len(output.Items) == 1 {
+			data = output.Items[0]
+		}
+
+		switch outputFormat {
+		case "json":
+			if err := writeJSON(os.Stdout, data, colorOutput); err != nil {
+				fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "yaml":
+			if err := writeYAML(os.Stdout, data); err != nil {
+				fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+				os.Exit(1)
+			}
+		case "jsonpath":
+			if err := printJSONPath(os.Stdout, data, outputTemplate); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case "go-template":
+			if err := printGoTemplate(os.Stdout, data, outputTemplate); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		case "name":
+			printNames(os.Stdout, output, namePrefix)
+		case "otel":
+			if err := printOtel(os.Stdout, output, colorOutput); err != nil {
+				fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "jsonl":
+			if err := printJSONLines(os.Stdout, output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "table":
+			printTable(output, cmdType, subCommand, namespaced, TableOptions{
+				Color:             colorOutput,
+				FullGVK:           fullGVK,
+				GroupByNamespace:  groupByNamespace,
+				GroupByAnnotation: groupByAnnotation,
+				SinceRevision:     sinceRevision,
+				Wide:              wide,
+				Layout:            tableLayout,
+				LabelColumns:      labelColumns,
+				MaxColWidth:       maxColWidth,
+				AbbrevNamespace:   abbrevNamespace,
+			})
+		case "csv", "tsv":
+			// Same columns as the table, one record per row, for spreadsheets and scripts
+			comma := ','
+			if outputFormat == "tsv" {
+				comma = '\t'
+			}
+			opts := TableOptions{FullGVK: fullGVK, SinceRevision: sinceRevision, Wide: wide, Layout: tableLayout, LabelColumns: labelColumns}
+			if err := printDelimited(os.Stdout, output, cmdType, subCommand, namespaced, opts, comma, excelCompat); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputFormat, err)
+				os.Exit(1)
+			}
+		}
+
+		// Teach what the scheduling fields mean, below their values
+		if explain && len(output.Items) > 0 {
+			printSchedulingExplanation(os.Stdout, subCommand, outputFormat == "yaml")
 		}
-		os.Exit(1)
 	}
+
+	os.Exit(exitCode)
 }
 
+// klogFlags holds the klog settings, only -v is exposed
+var klogFlags = flag.NewFlagSet("klog", flag.ContinueOnError)
+
 func init() {
 	// Add scheme for proper API discovery
 	_ = scheme.AddToScheme(scheme.Scheme)
+
+	// Initialize klog (client-go's logger), it logs to stderr
+	klog.InitFlags(klogFlags)
 }
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestErrorExitCode(t *testing.T) {
+	podsResource := schema.GroupResource{Resource: "pods"}
+	connectionRefused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "resource type not found", err: &resourceTypeNotFoundError{resourceType: "widgets"}, want: exitNotFound},
+		{name: "resource not found", err: fmt.Errorf("error getting web: %w", apierrors.NewNotFound(podsResource, "web")), want: exitNotFound},
+		{name: "forbidden", err: forbiddenError("list", testPodGVR, "prod"), want: exitForbidden},
+		{name: "forbidden from the API", err: apierrors.NewForbidden(podsResource, "", errors.New("denied")), want: exitForbidden},
+		{name: "discovery failed", err: fmt.Errorf("%w: %w", errDiscoveryFailed, connectionRefused), want: exitConnection},
+		{name: "connection refused while listing", err: fmt.Errorf("error listing resources: %w", connectionRefused), want: exitConnection},
+		{name: "other error", err: errors.New("resource name #1 is empty"), want: 1},
+		{name: "aggregate of not found", err: utilerrors.NewAggregate([]error{
+			fmt.Errorf("error getting web: %w", apierrors.NewNotFound(podsResource, "web")),
+			fmt.Errorf("error getting api: %w", apierrors.NewNotFound(podsResource, "api")),
+		}), want: exitNotFound},
+		{name: "aggregate of mixed errors", err: utilerrors.NewAggregate([]error{
+			apierrors.NewNotFound(podsResource, "web"),
+			fmt.Errorf("error listing resources: %w", connectionRefused),
+		}), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorExitCode(tt.err, true); got != tt.want {
+				t.Errorf("errorExitCode() = %d, want %d", got, tt.want)
+			}
+			if got := errorExitCode(tt.err, false); got != 1 {
+				t.Errorf("errorExitCode() without strict exit codes = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestValidateFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   outputFlags
+		wantErr string
+	}{
+		{name: "plain yaml", flags: outputFlags{cmdType: "labels", format: "yaml"}},
+		{name: "unknown format", flags: outputFlags{cmdType: "labels", format: "xml"}, wantErr: "unsupported output format 'xml'"},
+		{name: "table for labels", flags: outputFlags{cmdType: "labels", format: "table"}, wantErr: "table format is not supported for 'labels'"},
+		{name: "table as map", flags: outputFlags{cmdType: "owner", format: "table", asMap: true}, wantErr: "--as-map is only supported with json and yaml"},
+		{name: "json as map", flags: outputFlags{cmdType: "owner", format: "json", asMap: true}},
+		{name: "nested map", flags: outputFlags{cmdType: "owner", format: "json", asMap: true, nestByNamespace: true}, wantErr: "cannot be used together"},
+		{name: "yaml unwrap single", flags: outputFlags{cmdType: "labels", format: "yaml", unwrapSingle: true}},
+		{name: "table unwrap single", flags: outputFlags{cmdType: "owner", format: "table", unwrapSingle: true}, wantErr: "--unwrap-single is only supported with json and yaml"},
+		{name: "unwrap single as map", flags: outputFlags{cmdType: "labels", format: "json", unwrapSingle: true, asMap: true}, wantErr: "--unwrap-single cannot be used with --as-map"},
+		{name: "snapshot unwrap single", flags: outputFlags{cmdType: "labels", format: "yaml", snapshot: true, unwrapSingle: true}, wantErr: "snapshot saves the items as a list"},
+		{name: "context prefix without name output", flags: outputFlags{cmdType: "labels", format: "json", contextPrefix: true}, wantErr: "--context-prefix is only supported with name"},
+		{name: "context prefix from file", flags: outputFlags{cmdType: "labels", format: "name", contextPrefix: true, fromFile: true}, wantErr: "--context-prefix with -F requires --context"},
+		{name: "context prefix from file with context", flags: outputFlags{cmdType: "labels", format: "name", contextPrefix: true, fromFile: true, context: true}},
+		{name: "nest by namespace cluster scope", flags: outputFlags{cmdType: "labels", format: "json", nestByNamespace: true, resourceType: "all", scope: "cluster"}, wantErr: "--nest-by-namespace requires a namespaced resource type"},
+		{name: "watch without jsonl", flags: outputFlags{cmdType: "labels", format: "json", watch: true}, wantErr: "--watch is only supported with jsonl"},
+		{name: "watch snapshot", flags: outputFlags{cmdType: "labels", format: "jsonl", watch: true, snapshot: true}, wantErr: "--watch cannot be used with snapshot"},
+		{name: "watch timeout", flags: outputFlags{cmdType: "labels", format: "jsonl", watch: true, watchTimeout: 2 * time.Minute}},
+		{name: "watch timeout without watch", flags: outputFlags{cmdType: "labels", format: "jsonl", watchTimeout: time.Minute}, wantErr: "--watch-timeout is only supported with --watch"},
+		{name: "negative watch timeout", flags: outputFlags{cmdType: "labels", format: "jsonl", watch: true, watchTimeout: -time.Second}, wantErr: "--watch-timeout must not be negative"},
+		{name: "snapshot file", flags: outputFlags{cmdType: "labels", format: "yaml", snapshot: true, snapshotFile: "before.json"}},
+		{name: "snapshot file without snapshot", flags: outputFlags{cmdType: "labels", format: "yaml", snapshotFile: "before.json"}, wantErr: "--snapshot-file is only supported by the snapshot command"},
+		{name: "snapshot as map", flags: outputFlags{cmdType: "labels", format: "yaml", snapshot: true, asMap: true}, wantErr: "snapshot saves the items as a list"},
+		{name: "count by kind as csv", flags: outputFlags{cmdType: "owner", format: "csv", countByKind: true}, wantErr: "--count-by-kind is only supported with json, yaml and table"},
+		{name: "dedupe wide", flags: outputFlags{cmdType: "owner", format: "table", dedupe: true, wide: true}, wantErr: "--dedupe prints counts instead of resources and cannot be used with --wide"},
+		{name: "count by kind and dedupe", flags: outputFlags{cmdType: "owner", format: "json", countByKind: true, dedupe: true}, wantErr: "cannot be used together"},
+		{name: "merged table layout", flags: outputFlags{cmdType: "scheduling", subCommand: "resources", format: "csv", tableLayout: "merged"}},
+		{name: "with usage yaml", flags: outputFlags{cmdType: "scheduling", subCommand: "resources", format: "yaml", withUsage: true}},
+		{name: "with usage table", flags: outputFlags{cmdType: "scheduling", subCommand: "resources", format: "table", withUsage: true}, wantErr: "--with-usage is only supported with json, yaml, jsonl and template output"},
+		{name: "with usage csv", flags: outputFlags{cmdType: "scheduling", subCommand: "resources", format: "csv", withUsage: true}, wantErr: "--with-usage is only supported with json"},
+		{name: "merged layout for scheduling summary", flags: outputFlags{cmdType: "scheduling", format: "table", tableLayout: "merged"}, wantErr: "--table-layout=merged is only supported for 'scheduling resources'"},
+		{name: "resolve priority for labels", flags: outputFlags{cmdType: "labels", format: "yaml", resolvePriority: true}, wantErr: "--resolve-priority is only supported for 'scheduling priority'"},
+		{name: "resolve priority", flags: outputFlags{cmdType: "scheduling", subCommand: "priority", format: "yaml", resolvePriority: true}},
+		{name: "expand refs for command", flags: outputFlags{cmdType: "command", format: "yaml", expandRefs: true}, wantErr: "--expand-refs is only supported for 'env'"},
+		{name: "expand refs from file", flags: outputFlags{cmdType: "env", format: "yaml", expandRefs: true, fromFile: true}, wantErr: "--expand-refs needs to query the cluster and cannot be used with -F"},
+		{name: "dedupe identical for owner", flags: outputFlags{cmdType: "owner", format: "json", dedupeIdentical: true}, wantErr: "--dedupe-identical is only supported for 'labels' and 'annotations'"},
+		{name: "label columns for annotations", flags: outputFlags{cmdType: "annotations", format: "table", labelColumns: []string{"app"}}, wantErr: "--label-columns is only supported for 'labels'"},
+		{name: "diff namespace with one namespace", flags: outputFlags{cmdType: "labels", format: "table", diffNamespace: true, namespace: "prod"}, wantErr: "--diff-namespace compares two namespaces"},
+		{name: "watch several namespaces", flags: outputFlags{cmdType: "labels", format: "jsonl", watch: true, namespace: "a,b"}, wantErr: "--watch needs a single namespace"},
+		{name: "watch from file", flags: outputFlags{cmdType: "labels", format: "jsonl", watch: true, fromFile: true}, wantErr: "--watch needs to query the cluster"},
+		{name: "field selector with names", flags: outputFlags{cmdType: "labels", format: "yaml", fieldSelector: true, resourceNames: true}, wantErr: "field selectors only apply to lists and cannot be used with resource names"},
+		{name: "field selector with watched names", flags: outputFlags{cmdType: "labels", format: "jsonl", watch: true, fieldSelector: true, resourceNames: true}},
+		{name: "field selector", flags: outputFlags{cmdType: "labels", format: "yaml", fieldSelector: true}},
+		{name: "field selector from file", flags: outputFlags{cmdType: "labels", format: "yaml", fieldSelector: true, fromFile: true}, wantErr: "field selectors are evaluated by the API server"},
+		{name: "pdb from file", flags: outputFlags{cmdType: "pdb", format: "yaml", fromFile: true}, wantErr: "'pdb' command needs to query the cluster"},
+		{name: "resource type and file", flags: outputFlags{cmdType: "labels", format: "yaml", resourceType: "pods", fromFile: true}, wantErr: "resource type cannot be combined with -F"},
+		{name: "scope all", flags: outputFlags{cmdType: "labels", format: "yaml", resourceType: "all", scope: scopeNamespaced}},
+		{name: "scope for pods", flags: outputFlags{cmdType: "labels", format: "yaml", resourceType: "pods", scope: scopeCluster}, wantErr: "--scope only applies to the 'all' resource type"},
+		{name: "unknown scope", flags: outputFlags{cmdType: "labels", format: "yaml", resourceType: "all", scope: "global"}, wantErr: "--scope must be 'namespaced', 'cluster' or 'all'"},
+		{name: "group by annotation", flags: outputFlags{cmdType: "owner", format: "table", groupByAnnotation: "owner-team"}},
+		{name: "group by annotation as json", flags: outputFlags{cmdType: "owner", format: "json", groupByAnnotation: "owner-team"}, wantErr: "--group-by-annotation is only supported with table output"},
+		{name: "group by annotation and namespace", flags: outputFlags{cmdType: "owner", format: "table", groupByAnnotation: "owner-team", groupByNamespace: true}, wantErr: "--group-by-annotation and --group-by-namespace cannot be used together"},
+		{name: "redact with pointer to annotations", flags: outputFlags{cmdType: "annotations", format: "json", redact: true, jsonPointers: []string{"/metadata/annotations"}}, wantErr: "--json-pointer '/metadata/annotations' reaches the annotations and cannot be used with --redact"},
+		{name: "redact with pointer to one annotation", flags: outputFlags{cmdType: "annotations", format: "json", redact: true, jsonPointers: []string{"/metadata/uid", "/metadata/annotations/secret~1token"}}, wantErr: "cannot be used with --redact"},
+		{name: "redact with pointer to metadata", flags: outputFlags{cmdType: "annotations", format: "json", redact: true, jsonPointers: []string{"/metadata"}}, wantErr: "cannot be used with --redact"},
+		{name: "redact with pointer to the whole object", flags: outputFlags{cmdType: "annotations", format: "json", redact: true, jsonPointers: []string{""}}, wantErr: "cannot be used with --redact"},
+		{name: "redact with pointer elsewhere", flags: outputFlags{cmdType: "annotations", format: "json", redact: true, jsonPointers: []string{"/metadata/labels", "/spec/nodeName"}}},
+		{name: "merged layout as yaml", flags: outputFlags{cmdType: "scheduling", format: "yaml", tableLayout: "merged"}, wantErr: "--table-layout is only supported with table, csv and tsv"},
+		{name: "unknown table layout", flags: outputFlags{cmdType: "scheduling", format: "table", tableLayout: "compact"}, wantErr: "unknown --table-layout 'compact'"},
+		{name: "json pointer", flags: outputFlags{cmdType: "labels", format: "jsonl", jsonPointers: []string{"/metadata/uid"}}},
+		{name: "json pointer table", flags: outputFlags{cmdType: "owner", format: "table", jsonPointers: []string{"/metadata/uid"}}, wantErr: "--json-pointer is only supported with json, yaml, jsonl"},
+		{name: "invalid json pointer", flags: outputFlags{cmdType: "labels", format: "json", jsonPointers: []string{"metadata.uid"}}, wantErr: "invalid JSON pointer 'metadata.uid'"},
+		{name: "dedupe identical table", flags: outputFlags{cmdType: "annotations", format: "table", dedupeIdentical: true}},
+		{name: "dedupe identical jsonl", flags: outputFlags{cmdType: "labels", format: "jsonl", dedupeIdentical: true}, wantErr: "--dedupe-identical is only supported with json, yaml and table"},
+		{name: "dedupe identical and count unique", flags: outputFlags{cmdType: "labels", format: "json", dedupeIdentical: true, countUnique: "app"}, wantErr: "--dedupe-identical and --count-unique cannot be used together"},
+		{name: "diff namespace table", flags: outputFlags{cmdType: "labels", format: "table", diffNamespace: true, namespace: "staging,prod"}},
+		{name: "diff namespace as map", flags: outputFlags{cmdType: "labels", format: "json", diffNamespace: true, asMap: true}, wantErr: "--diff-namespace prints counts instead of resources"},
+		{name: "diff namespace and count unique", flags: outputFlags{cmdType: "labels", format: "json", diffNamespace: true, countUnique: "app"}, wantErr: "cannot be used together"},
+		{name: "wide json", flags: outputFlags{cmdType: "owner", format: "json", wide: true}, wantErr: "--wide is only supported"},
+		{name: "full gvk yaml", flags: outputFlags{cmdType: "owner", format: "yaml", fullGVK: true}, wantErr: "--full-gvk is only supported"},
+		{name: "negative max col width", flags: outputFlags{cmdType: "owner", format: "table", maxColWidth: -1}, wantErr: "must not be negative"},
+		{name: "negative concurrency", flags: outputFlags{cmdType: "labels", format: "json", concurrency: -1}, wantErr: "--concurrency must not be negative"},
+		{name: "concurrency", flags: outputFlags{cmdType: "labels", format: "json", namespace: "staging,prod", concurrency: 2}},
+		{name: "abbrev namespace csv", flags: outputFlags{cmdType: "owner", format: "csv", abbrevNamespace: true}, wantErr: "--abbrev-namespace is only supported"},
+		{name: "abbrev and group by namespace", flags: outputFlags{cmdType: "owner", format: "table", abbrevNamespace: true, groupByNamespace: true}, wantErr: "cannot be used together"},
+		{name: "explain json", flags: outputFlags{cmdType: "scheduling", format: "json", explain: true}, wantErr: "--explain is only supported"},
+		{name: "explain table", flags: outputFlags{cmdType: "scheduling", format: "table", explain: true}},
+		{name: "group by namespace json", flags: outputFlags{cmdType: "owner", format: "json", groupByNamespace: true}, wantErr: "use --nest-by-namespace"},
+		{name: "excel compat json", flags: outputFlags{cmdType: "owner", format: "json", excelCompat: true}, wantErr: "--excel-compat is only supported"},
+		{name: "label columns table", flags: outputFlags{cmdType: "labels", format: "table", labelColumns: []string{"app"}}},
+		{name: "label columns yaml", flags: outputFlags{cmdType: "labels", format: "yaml", labelColumns: []string{"app"}}, wantErr: "--label-columns is only supported with table, csv and tsv"},
+		{name: "label columns count", flags: outputFlags{cmdType: "labels", format: "table", labelColumns: []string{"app"}, countUnique: "app"}, wantErr: "--label-columns cannot be used with --count-unique"},
+		{name: "legend json", flags: outputFlags{cmdType: "labels", format: "json", color: true, legend: true}},
+		{name: "legend without color", flags: outputFlags{cmdType: "labels", format: "json", legend: true}, wantErr: "add -c"},
+		{name: "legend yaml", flags: outputFlags{cmdType: "labels", format: "yaml", color: true, legend: true}, wantErr: "--legend is only supported with json, otel and table"},
+		{name: "legend count table", flags: outputFlags{cmdType: "owner", format: "table", color: true, legend: true, dedupe: true}, wantErr: "--legend cannot be used with --dedupe"},
+		{name: "spec path jsonl", flags: outputFlags{cmdType: "scheduling", format: "jsonl", showSpecPath: true}},
+		{name: "spec path csv", flags: outputFlags{cmdType: "scheduling", format: "csv", showSpecPath: true}, wantErr: "--show-spec-path is only supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFlags(tt.flags)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateFlags() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateFlags() error = %v, want %q", err, tt.wantErr)
+			}
+			if got := errorExitCode(err, true); got != exitInvalidFlags {
+				t.Errorf("errorExitCode() = %d, want %d", got, exitInvalidFlags)
+			}
+		})
+	}
+}
+
+func TestValidateNamespacedFlags(t *testing.T) {
+	flags := outputFlags{cmdType: "labels", format: "json", nestByNamespace: true}
+	if err := validateNamespacedFlags(flags, true); err != nil {
+		t.Errorf("validateNamespacedFlags() error = %v, want nil", err)
+	}
+	err := validateNamespacedFlags(flags, false)
+	if err == nil || errorExitCode(err, true) != exitInvalidFlags {
+		t.Errorf("validateNamespacedFlags() error = %v, want an invalid flags error", err)
+	}
+}
+
+func TestTargetNamespace(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	content := `apiVersion: v1
+kind: Config
+clusters: [{name: kind, cluster: {server: "https://127.0.0.1:6443"}}]
+users: [{name: admin, user: {}}]
+contexts: [{name: dev, context: {cluster: kind, user: admin, namespace: team-a}}]
+current-context: dev
+`
+	if err := os.WriteFile(kubeconfig, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	tests := []struct {
+		name          string
+		namespace     string
+		allNamespaces bool
+		namespaced    bool
+		noFallback    bool
+		want          string
+		wantErr       bool
+	}{
+		{name: "explicit namespace", namespace: "prod", namespaced: true, noFallback: true, want: "prod"},
+		{name: "all namespaces", namespace: "prod", allNamespaces: true, namespaced: true, noFallback: true, want: ""},
+		{name: "kubeconfig namespace", namespaced: true, want: "team-a"},
+		{name: "no fallback", namespaced: true, noFallback: true, wantErr: true},
+		{name: "cluster-scoped without namespace", noFallback: true, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := targetNamespace(tt.namespace, tt.allNamespaces, tt.namespaced, tt.noFallback, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("targetNamespace() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("targetNamespace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountByOwnerKind(t *testing.T) {
+	items := []OutputItem{
+		{Name: "web-1", OwnerReferences: []OwnerReference{{Kind: "ReplicaSet", Name: "web", Controller: true}}},
+		// The controller is counted, not the owner listed first
+		{Name: "web-2", OwnerReferences: []OwnerReference{{Kind: "ConfigMap", Name: "settings"}, {Kind: "ReplicaSet", Name: "web", Controller: true}}},
+		// Without a controller, the first owner is counted
+		{Name: "cache", OwnerReferences: []OwnerReference{{Kind: "ConfigMap", Name: "settings"}, {Kind: "Secret", Name: "token"}}},
+		{Name: "etcd", OwnerReferences: []OwnerReference{{Kind: "Node", Name: "node-1", Controller: true}}},
+		{Name: "debug"},
+	}
+
+	want := []OwnerKindCount{
+		{Kind: "ReplicaSet", Count: 2},
+		{Kind: "<none>", Count: 1},
+		{Kind: "ConfigMap", Count: 1},
+		{Kind: "Node (static)", Count: 1},
+	}
+	if got := countByOwnerKind(items); !reflect.DeepEqual(got, want) {
+		t.Errorf("countByOwnerKind() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCountLabelValues(t *testing.T) {
+	item := func(labels map[string]string) OutputItem {
+		return OutputItem{Labels: &labels}
+	}
+	items := []OutputItem{
+		item(map[string]string{"version": "1.2"}),
+		item(map[string]string{"version": "1.3"}),
+		item(map[string]string{"version": "1.2"}),
+		item(map[string]string{"app": "web"}),
+		{},
+	}
+
+	want := LabelValueCounts{
+		Label:    "version",
+		Distinct: 2,
+		Values:   []LabelValueCount{{Value: "1.2", Count: 2}, {Value: "1.3", Count: 1}},
+		Missing:  2,
+	}
+	if got := countLabelValues(items, "version"); !reflect.DeepEqual(got, want) {
+		t.Errorf("countLabelValues() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDedupeIdenticalMaps(t *testing.T) {
+	web := map[string]string{"app": "web", "tier": "frontend"}
+	sameWeb := map[string]string{"tier": "frontend", "app": "web"}
+	api := map[string]string{"app": "api"}
+	items := []OutputItem{
+		{Name: "api-1", Namespace: "prod", Labels: &api},
+		{Name: "web-1", Namespace: "prod", Labels: &web},
+		{Name: "web-2", Namespace: "staging", Labels: &sameWeb},
+		{Name: "job-1", Namespace: "prod"},
+	}
+
+	want := []IdenticalMapCount{
+		{Values: web, Count: 2, Example: "prod/web-1"},
+		{Values: api, Count: 1, Example: "prod/api-1"},
+		{Values: map[string]string{}, Count: 1, Example: "prod/job-1"},
+	}
+	if got := dedupeIdenticalMaps(items, "labels"); !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeIdenticalMaps() = %+v, want %+v", got, want)
+	}
+
+	// Annotations are grouped the same way, here none of the resources has any
+	if got := dedupeIdenticalMaps(items, "annotations"); len(got) != 1 || got[0].Count != 4 {
+		t.Errorf("dedupeIdenticalMaps(annotations) = %+v, want a single set of 4", got)
+	}
+}
+
+func TestDiffNamespaceLabels(t *testing.T) {
+	item := func(namespace string, labels map[string]string) OutputItem {
+		return OutputItem{Namespace: namespace, Labels: &labels}
+	}
+	items := []OutputItem{
+		item("staging", map[string]string{"app": "web", "team": "payments"}),
+		item("staging", map[string]string{"app": "api"}),
+		item("prod", map[string]string{"app": "web", "tier": "frontend"}),
+		{Namespace: "prod"},
+	}
+
+	want := NamespaceLabelDiff{
+		Namespaces: []string{"staging", "prod"},
+		Resources:  []int{2, 2},
+		Common:     []LabelKeyUsage{{Key: "app", Counts: []int{2, 1}}},
+		OnlyIn: map[string][]LabelKeyUsage{
+			"staging": {{Key: "team", Counts: []int{1, 0}}},
+			"prod":    {{Key: "tier", Counts: []int{0, 1}}},
+		},
+	}
+	if got := diffNamespaceLabels(items, []string{"staging", "prod"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffNamespaceLabels() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHasNonEmptyResult(t *testing.T) {
+	emptyLabels := map[string]string{}
+	labels := map[string]string{"app": "web"}
+
+	tests := []struct {
+		name    string
+		item    OutputItem
+		cmdType string
+		want    bool
+	}{
+		{name: "no labels", item: OutputItem{Labels: &emptyLabels}, cmdType: "labels", want: false},
+		{name: "labels", item: OutputItem{Labels: &labels}, cmdType: "labels", want: true},
+		{name: "no owner", item: OutputItem{}, cmdType: "owner", want: false},
+		{name: "container without command", item: OutputItem{Commands: []ContainerCommand{{Name: "app"}}}, cmdType: "command", want: false},
+		{name: "container with args", item: OutputItem{Commands: []ContainerCommand{{Name: "app", Args: []string{"--debug"}}}}, cmdType: "command", want: true},
+		{name: "scheduling defaults only", item: OutputItem{Scheduling: &SchedulingInfo{NodeName: "node-1", SchedulerName: "default-scheduler"}}, cmdType: "scheduling", want: false},
+		{name: "scheduling tolerations", item: OutputItem{Scheduling: &SchedulingInfo{Tolerations: []interface{}{map[string]interface{}{"key": "gpu"}}}}, cmdType: "scheduling", want: true},
+		{name: "scheduling subcommand unset", item: OutputItem{}, cmdType: "scheduling", want: false},
+		{name: "scheduling subcommand set", item: OutputItem{NodeSelector: map[string]string{"disk": "ssd"}}, cmdType: "scheduling", want: true},
+		{name: "replicas always kept", item: OutputItem{Replicas: &ReplicasInfo{}}, cmdType: "replicas", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasNonEmptyResult(tt.item, tt.cmdType); got != tt.want {
+				t.Errorf("hasNonEmptyResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitNamespaces(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"prod", []string{"prod"}},
+		{"staging, prod,,staging", []string{"staging", "prod"}},
+	}
+	for _, tt := range tests {
+		if got := splitNamespaces(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitNamespaces(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRedactAnnotations(t *testing.T) {
+	annotations := map[string]string{
+		"vault.hashicorp.com/role": "web",
+		"example.com/api-token":    "s3cr3t",
+		"owner":                    "team-a",
+	}
+	got := redactAnnotations(annotations, []string{"vault.hashicorp.com/", "*token*"})
+	want := map[string]string{
+		"vault.hashicorp.com/role": redactedValue,
+		"example.com/api-token":    redactedValue,
+		"owner":                    "team-a",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("redactAnnotations() = %v, want %v", got, want)
+	}
+
+	// The placeholder is written as is in JSON, not as escaped characters
+	var buf strings.Builder
+	if err := writeJSON(&buf, got, false); err != nil {
+		t.Fatalf("writeJSON() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"`+redactedValue+`"`) {
+		t.Errorf("writeJSON() = %s, want the placeholder %q unescaped", buf.String(), redactedValue)
+	}
+}
+
+func TestAnnotationValueFilter(t *testing.T) {
+	filter, err := parseAnnotationValueFilter("checksum/config=^3f2a")
+	if err != nil {
+		t.Fatalf("parseAnnotationValueFilter() error = %v", err)
+	}
+	tests := []struct {
+		annotations map[string]string
+		want        bool
+	}{
+		{map[string]string{"checksum/config": "3f2a9c"}, true},
+		{map[string]string{"checksum/config": "9c3f2a"}, false},
+		{map[string]string{"checksum/secret": "3f2a9c"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := filter.matches(tt.annotations); got != tt.want {
+			t.Errorf("matches(%v) = %v, want %v", tt.annotations, got, tt.want)
+		}
+	}
+
+	// The pattern may contain "=", only the first one separates the key
+	if filter, err := parseAnnotationValueFilter("example.com/query=a=b"); err != nil || filter.key != "example.com/query" || filter.pattern.String() != "a=b" {
+		t.Errorf("parseAnnotationValueFilter(a=b) = %+v, %v", filter, err)
+	}
+	for _, value := range []string{"checksum/config", "=^3f2a", "checksum/config=(["} {
+		if _, err := parseAnnotationValueFilter(value); err == nil {
+			t.Errorf("parseAnnotationValueFilter(%q) expected an error", value)
+		}
+	}
+}
+
+func TestCheckStrictArgs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("n", "", "namespace")
+	fs.String("output", "", "output format")
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"web-1", "db-1"}},
+		{args: nil},
+		{args: []string{"web-1", "--namesapce", "prod"}, wantErr: "unknown flag --namesapce"},
+		{args: []string{"web-1", "-n", "prod"}, wantErr: "flag -n comes after the resource names"},
+		{args: []string{"web-1", "--output=json"}, wantErr: "flag --output=json comes after the resource names"},
+		{args: []string{"web-1", "--", "db-1"}, wantErr: "'--' after the resource names"},
+	}
+	for _, tt := range tests {
+		err := checkStrictArgs(fs, tt.args)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("checkStrictArgs(%q) error = %v, want nil", tt.args, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !errors.As(err, new(invalidFlagsError)) {
+			t.Errorf("checkStrictArgs(%q) error = %v, want an invalid flags error containing %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
@@ -1,38 +1,78 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"regexp"
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ANSI colors of -c, package-level so --legend shows the same ones
+const (
+	colorReset = "\033[0m"
+
+	// Colorized JSON (similar to jq)
+	keyColor   = "\033[1;34m" // bold blue for keys
+	strColor   = "\033[32m"   // green for strings
+	numColor   = "\033[33m"   // yellow for numbers
+	boolColor  = "\033[1;33m" // bold yellow for booleans
+	nullColor  = "\033[90m"   // gray for null
+	punctColor = "\033[37m"   // white for punctuation
+
+	// Colorized tables
+	recentColor = "\033[33m" // yellow for recently created resources
+	stuckColor  = "\033[31m" // red for resources stuck terminating
+	dimColor    = "\033[2m"  // dim for <none>
 )
 
+// printColorLegend writes the one-line key of the -c colors of a format (--legend), each entry in its color
+func printColorLegend(w io.Writer, format string) {
+	entries := []struct{ color, text string }{
+		{keyColor, "keys"}, {strColor, "strings"}, {numColor, "numbers"}, {boolColor, "booleans"}, {nullColor, "null"},
+	}
+	if format == "table" {
+		entries = []struct{ color, text string }{
+			{recentColor, fmt.Sprintf("created in the last %d minutes", int(recentAge.Minutes()))}, {stuckColor, "stuck terminating"}, {dimColor, "<none>"},
+		}
+	}
+
+	parts := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		parts = append(parts, entry.color+entry.text+colorReset)
+	}
+	fmt.Fprintf(w, "Legend: %s\n", strings.Join(parts, ", "))
+}
+
 // colorizeJSON adds ANSI color codes to JSON output (similar to jq)
 func colorizeJSON(jsonStr string) string {
-	const (
-		reset      = "\033[0m"
-		keyColor   = "\033[1;34m" // bold blue for keys
-		strColor   = "\033[32m"   // green for strings
-		numColor   = "\033[33m"   // yellow for numbers
-		boolColor  = "\033[1;33m" // bold yellow for booleans
-		nullColor  = "\033[90m"   // gray for null
-		punctColor = "\033[37m"   // white for punctuation
-	)
-
 	result := jsonStr
 
 	// Colorize punctuation first ({, }, [, ])
 	punctRegex := regexp.MustCompile(`([{}\[\]])`)
 	result = punctRegex.ReplaceAllStringFunc(result, func(match string) string {
-		return punctColor + match + reset
+		return punctColor + match + colorReset
 	})
 
 	// Colorize keys (pattern: "key":)
 	keyRegex := regexp.MustCompile(`"([^"]+)":`)
 	result = keyRegex.ReplaceAllStringFunc(result, func(match string) string {
-		return keyColor + match + reset
+		return keyColor + match + colorReset
 	})
 
 	// Colorize strings (values in quotes that are not keys)
@@ -41,9 +81,9 @@ func colorizeJSON(jsonStr string) string {
 	result = strRegex.ReplaceAllStringFunc(result, func(match string) string {
 		// Preserve the ":" and spaces, colorize only the string
 		if strings.HasPrefix(match, ": ") {
-			return ": " + strColor + `"` + strings.TrimPrefix(strings.TrimSuffix(match[2:], `"`), `"`) + `"` + reset
+			return ": " + strColor + `"` + strings.TrimPrefix(strings.TrimSuffix(match[2:], `"`), `"`) + `"` + colorReset
 		} else if strings.HasPrefix(match, ":") {
-			return ":" + strColor + match[1:] + reset
+			return ":" + strColor + match[1:] + colorReset
 		}
 		return match
 	})
@@ -53,7 +93,7 @@ func colorizeJSON(jsonStr string) string {
 	result = numRegex.ReplaceAllStringFunc(result, func(match string) string {
 		parts := strings.SplitN(match, ":", 2)
 		if len(parts) == 2 {
-			return parts[0] + ":" + numColor + strings.TrimSpace(parts[1]) + reset
+			return parts[0] + ":" + numColor + strings.TrimSpace(parts[1]) + colorReset
 		}
 		return match
 	})
@@ -63,7 +103,7 @@ func colorizeJSON(jsonStr string) string {
 	result = boolRegex.ReplaceAllStringFunc(result, func(match string) string {
 		parts := strings.SplitN(match, ":", 2)
 		if len(parts) == 2 {
-			return parts[0] + ":" + boolColor + strings.TrimSpace(parts[1]) + reset
+			return parts[0] + ":" + boolColor + strings.TrimSpace(parts[1]) + colorReset
 		}
 		return match
 	})
@@ -73,7 +113,7 @@ func colorizeJSON(jsonStr string) string {
 	result = nullRegex.ReplaceAllStringFunc(result, func(match string) string {
 		parts := strings.SplitN(match, ":", 2)
 		if len(parts) == 2 {
-			return parts[0] + ":" + nullColor + strings.TrimSpace(parts[1]) + reset
+			return parts[0] + ":" + nullColor + strings.TrimSpace(parts[1]) + colorReset
 		}
 		return match
 	})
@@ -81,15 +121,1154 @@ func colorizeJSON(jsonStr string) string {
 	return result
 }
 
+// recentAge is the age below which a resource is highlighted in colored table output
+const recentAge = 5 * time.Minute
+
+// now returns the current time, tests override it to get a deterministic clock
+var now = time.Now
+
+// itemAge returns how long ago the resource was created, zero when the timestamp is unknown
+func itemAge(item OutputItem) time.Duration {
+	if item.CreationTimestamp.IsZero() {
+		return 0
+	}
+	return now().Sub(item.CreationTimestamp)
+}
+
+// isRecent reports whether the resource was created less than recentAge ago
+func isRecent(item OutputItem) bool {
+	return !item.CreationTimestamp.IsZero() && itemAge(item) < recentAge
+}
+
+// splitOutputTemplate splits "jsonpath=<template>" and "go-template=<template>" into the format and the template
+// The format is lowercased, the template is returned as is
+func splitOutputTemplate(outputFormat string) (string, string) {
+	format, template, found := strings.Cut(outputFormat, "=")
+	format = strings.ToLower(format)
+	if !found || (format != "jsonpath" && format != "go-template") {
+		return strings.ToLower(outputFormat), ""
+	}
+	return format, template
+}
+
+// toTemplateData converts the output to plain maps and slices keyed by the JSON field names,
+// so templates use the same names as the JSON output and can range over every nested list
+func toTemplateData(data interface{}) (interface{}, error) {
+	jsonOutput, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling JSON: %v", err)
+	}
+
+	var templateData interface{}
+	if err := json.Unmarshal(jsonOutput, &templateData); err != nil {
+		return nil, fmt.Errorf("error unmarshaling JSON: %v", err)
+	}
+	return templateData, nil
+}
+
+// printJSONPath outputs the data through a kubectl-style JSONPath template, e.g. {.items[*].name}
+func printJSONPath(out io.Writer, data interface{}, template string) error {
+	templateData, err := toTemplateData(data)
+	if err != nil {
+		return err
+	}
+
+	// Like kubectl, accept ".items[*].name" without braces
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(template); err != nil {
+		return fmt.Errorf("error parsing jsonpath %s: %v", template, err)
+	}
+	if err := parser.Execute(out, templateData); err != nil {
+		return fmt.Errorf("error executing jsonpath %s: %v", template, err)
+	}
+	return nil
+}
+
+// printGoTemplate outputs the data through a Go template, e.g. {{range .items}}{{.name}}{{end}}
+func printGoTemplate(out io.Writer, data interface{}, templateText string) error {
+	templateData, err := toTemplateData(data)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("output").Parse(templateText)
+	if err != nil {
+		return fmt.Errorf("error parsing go-template: %v", err)
+	}
+	if err := tmpl.Execute(out, templateData); err != nil {
+		return fmt.Errorf("error executing go-template: %v", err)
+	}
+	return nil
+}
+
+// outputItemKey returns the key identifying a resource in the output (namespace/name or name)
+func outputItemKey(item OutputItem) string {
+	if item.Namespace != "" {
+		return item.Namespace + "/" + item.Name
+	}
+	return item.Name
+}
+
+// outputAsMap returns the output items keyed by namespace/name (or name for cluster-scoped resources)
+// so they can be looked up directly, e.g. jq '."default/web".labels'
+// When the items are of several types the key starts with the type (pod/default/web), so a
+// Service and a Deployment of the same name don't overwrite each other
+func outputAsMap(output Output) map[string]OutputItem {
+	multipleTypes := false
+	for _, item := range output.Items {
+		if item.ResourceType != output.Items[0].ResourceType {
+			multipleTypes = true
+			break
+		}
+	}
+
+	itemsByKey := make(map[string]OutputItem, len(output.Items))
+	for _, item := range output.Items {
+		key := outputItemKey(item)
+		if multipleTypes {
+			key = item.ResourceType + "/" + key
+		}
+		itemsByKey[key] = item
+	}
+	return itemsByKey
+}
+
+// withTrailingNewline makes output end with exactly one newline, whatever the formatter produced,
+// so the output of every format can be diffed and snapshotted the same way
+func withTrailingNewline(s string) string {
+	return strings.TrimRight(s, "\n") + "\n"
+}
+
+// writeJSON writes data as indented JSON, colorized when color is set
+func writeJSON(w io.Writer, data interface{}, color bool) error {
+	jsonOutput, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	out := string(jsonOutput)
+	if color {
+		out = colorizeJSON(out)
+	}
+	_, err = io.WriteString(w, withTrailingNewline(out))
+	return err
+}
+
+// writeYAML writes data as YAML
+func writeYAML(w io.Writer, data interface{}) error {
+	yamlOutput, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, withTrailingNewline(string(yamlOutput)))
+	return err
+}
+
+// printOwnerKindCounts outputs the owner kind tally in the requested format
+func printOwnerKindCounts(counts []OwnerKindCount, outputFormat string, colorOutput bool) {
+	switch outputFormat {
+	case "json":
+		if err := writeJSON(os.Stdout, OwnerKindCounts{Counts: counts}, colorOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(os.Stdout, OwnerKindCounts{Counts: counts}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		total := 0
+		fmt.Fprintf(w, "OWNER KIND\tCOUNT\n")
+		fmt.Fprintf(w, "----------\t-----\n")
+		for _, c := range counts {
+			fmt.Fprintf(w, "%s\t%d\n", c.Kind, c.Count)
+			total += c.Count
+		}
+		fmt.Fprintf(w, "TOTAL\t%d\n", total)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
+		os.Exit(1)
+	}
+}
+
+// printLabelValueCounts outputs the distinct values of a label in the requested format
+func printLabelValueCounts(counts LabelValueCounts, outputFormat string, colorOutput bool) {
+	switch outputFormat {
+	case "json":
+		if err := writeJSON(os.Stdout, counts, colorOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(os.Stdout, counts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		fmt.Fprintf(w, "VALUE\tCOUNT\n")
+		fmt.Fprintf(w, "-----\t-----\n")
+		for _, c := range counts.Values {
+			value := c.Value
+			if value == "" {
+				// Set to an empty string, unlike resources without the label
+				value = `""`
+			}
+			fmt.Fprintf(w, "%s\t%d\n", value, c.Count)
+		}
+		if counts.Missing > 0 {
+			fmt.Fprintf(w, "<none>\t%d\n", counts.Missing)
+		}
+		fmt.Fprintf(w, "DISTINCT\t%d\n", counts.Distinct)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
+		os.Exit(1)
+	}
+}
+
+// printNamespaceLabelDiff outputs the comparison of the label keys of two namespaces in the requested format
+func printNamespaceLabelDiff(diff NamespaceLabelDiff, outputFormat string, colorOutput bool) {
+	switch outputFormat {
+	case "json":
+		if err := writeJSON(os.Stdout, diff, colorOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(os.Stdout, diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		// One column per namespace with the share of its resources carrying the key
+		fmt.Fprintf(w, "KEY\t%s\tUSED IN\n", strings.ToUpper(strings.Join(diff.Namespaces, "\t")))
+		fmt.Fprintf(w, "---\t%s\t-------\n", strings.TrimSuffix(strings.Repeat("-----\t", len(diff.Namespaces)), "\t"))
+		printRow := func(usage LabelKeyUsage, usedIn string) {
+			cells := make([]string, len(usage.Counts))
+			for i, count := range usage.Counts {
+				cells[i] = fmt.Sprintf("%d/%d", count, diff.Resources[i])
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", usage.Key, strings.Join(cells, "\t"), usedIn)
+		}
+		for _, usage := range diff.Common {
+			printRow(usage, "both")
+		}
+		for _, namespace := range diff.Namespaces {
+			for _, usage := range diff.OnlyIn[namespace] {
+				printRow(usage, namespace+" only")
+			}
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
+		os.Exit(1)
+	}
+}
+
+// printNames writes one <type>/<name> line per item, like kubectl's -o name
+// prefix (e.g. "staging/") tells apart the same name coming from several clusters
+func printNames(w io.Writer, output Output, prefix string) {
+	for _, item := range output.Items {
+		name := item.Name
+		if item.ResourceType != "" {
+			name = item.ResourceType + "/" + name
+		}
+		fmt.Fprintln(w, prefix+name)
+	}
+}
+
+// printJSONLines writes one compact JSON object per item (JSON Lines), for log processors and streaming tools
+func printJSONLines(w io.Writer, output Output) error {
+	encoder := json.NewEncoder(w)
+	for _, item := range output.Items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// otelAttributes maps an item to OpenTelemetry resource attributes (semantic conventions): the namespace
+// goes to k8s.namespace.name, the name to k8s.<kind>.name and labels and annotations to
+// k8s.<kind>.label.<key> and k8s.<kind>.annotation.<key>. The conventions cover pods, workloads,
+// namespaces and nodes, other kinds follow the same pattern.
+func otelAttributes(item OutputItem) map[string]string {
+	kind, _, _ := strings.Cut(item.ResourceType, ".")
+	attributes := map[string]string{
+		"k8s." + kind + ".name": item.Name,
+	}
+	if item.Namespace != "" {
+		attributes["k8s.namespace.name"] = item.Namespace
+	}
+	if item.Labels != nil {
+		for key, value := range *item.Labels {
+			attributes["k8s."+kind+".label."+key] = value
+		}
+	}
+	if item.Annotations != nil {
+		for key, value := range *item.Annotations {
+			attributes["k8s."+kind+".annotation."+key] = value
+		}
+	}
+	return attributes
+}
+
+// printOtel writes the OpenTelemetry resource attributes of each item as JSON (-o otel)
+func printOtel(w io.Writer, output Output, color bool) error {
+	otel := OtelOutput{Resources: make([]OtelResource, 0, len(output.Items))}
+	for _, item := range output.Items {
+		otel.Resources = append(otel.Resources, OtelResource{Attributes: otelAttributes(item)})
+	}
+	return writeJSON(w, otel, color)
+}
+
+// printOwnerCounts outputs the deduplicated owners in the requested format
+func printOwnerCounts(owners []OwnerCount, outputFormat string, colorOutput bool, namespaced bool) {
+	switch outputFormat {
+	case "json":
+		if err := writeJSON(os.Stdout, OwnerCounts{Owners: owners}, colorOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(os.Stdout, OwnerCounts{Owners: owners}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		if namespaced {
+			fmt.Fprintf(w, "OWNER NAMESPACE\tOWNER KIND\tOWNER NAME\tCOUNT\n")
+			fmt.Fprintf(w, "---------------\t----------\t----------\t-----\n")
+		} else {
+			fmt.Fprintf(w, "OWNER KIND\tOWNER NAME\tCOUNT\n")
+			fmt.Fprintf(w, "----------\t----------\t-----\n")
+		}
+		for _, o := range owners {
+			if namespaced {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", o.Namespace, o.Kind, o.Name, o.Count)
+			} else {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", o.Kind, o.Name, o.Count)
+			}
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
+		os.Exit(1)
+	}
+}
+
+// printIdenticalMapCounts outputs the sets of identical labels or annotations in the requested format
+func printIdenticalMapCounts(sets []IdenticalMapCount, cmdType string, outputFormat string, colorOutput bool) {
+	switch outputFormat {
+	case "json":
+		if err := writeJSON(os.Stdout, IdenticalMapCounts{Sets: sets}, colorOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(os.Stdout, IdenticalMapCounts{Sets: sets}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		defer w.Flush()
+
+		fmt.Fprintf(w, "%s\tCOUNT\tEXAMPLE\n", strings.ToUpper(cmdType))
+		fmt.Fprintf(w, "%s\t-----\t-------\n", strings.Repeat("-", len(cmdType)))
+		for _, set := range sets {
+			pairs := make([]string, 0, len(set.Values))
+			for key, value := range set.Values {
+				pairs = append(pairs, key+"="+value)
+			}
+			sort.Strings(pairs)
+			values := strings.Join(pairs, ",")
+			if values == "" {
+				values = "<none>"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\n", values, set.Count, set.Example)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: json, yaml, table\n", outputFormat)
+		os.Exit(1)
+	}
+}
+
+// TableOptions holds the flags that change how tables are rendered
+type TableOptions struct {
+	// Color lightly colors cells when stdout is a terminal (see colorizeTable)
+	Color bool
+	// FullGVK shows owner references as apiVersion/kind instead of kind only
+	FullGVK bool
+	// GroupByNamespace prints one table per namespace instead of a NAMESPACE column
+	GroupByNamespace bool
+	// GroupByAnnotation prints one table per value of this annotation (see OutputItem.AnnotationGroup)
+	GroupByAnnotation string
+	// SinceRevision adds a REVISION column to owner tables (current or previous Deployment revision)
+	SinceRevision bool
+	// Wide expands summarized cells, e.g. affinity rules instead of "present"
+	Wide bool
+	// MaxColWidth shortens longer cells to this many characters, 0 means no limit
+	MaxColWidth int
+	// AbbrevNamespace shortens namespace prefixes shared by several namespaces, explained by a legend
+	AbbrevNamespace bool
+	// Layout "merged" shows the request and limit of each resource side by side (scheduling resources)
+	Layout string
+	// LabelColumns replaces the LABELS cell with one column per label key, like kubectl get -L (labels)
+	LabelColumns []string
+}
+
 // printTable outputs the data in table format
-func printTable(output Output, cmdType string, subCommand string, namespaced bool) {
+func printTable(output Output, cmdType string, subCommand string, namespaced bool, opts TableOptions) {
 	if len(output.Items) == 0 {
 		return
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	// The legend comes after the table, whichever way it is split into sections
+	if opts.AbbrevNamespace && namespaced && !opts.GroupByNamespace {
+		var legend []namespaceAbbreviation
+		output.Items, legend = abbreviateNamespaces(output.Items)
+		defer printNamespaceLegend(os.Stdout, legend)
+	}
+
+	// One section per value of the annotation, resources without it come under <none>
+	if opts.GroupByAnnotation != "" {
+		groups := groupItems(output.Items, func(item OutputItem) string { return item.AnnotationGroup })
+		for i, group := range groups {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s: %s\n", opts.GroupByAnnotation, group[0].AnnotationGroup)
+			printTableSection(Output{Items: group}, cmdType, subCommand, namespaced, opts)
+		}
+		return
+	}
+
+	// One section per namespace, introduced by a header row
+	if opts.GroupByNamespace && namespaced {
+		for i, group := range groupItemsByNamespace(output.Items) {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("NAMESPACE: %s\n", group[0].Namespace)
+			printTableSection(Output{Items: group}, cmdType, subCommand, false, opts)
+		}
+		return
+	}
+
+	printTableSection(output, cmdType, subCommand, namespaced, opts)
+}
+
+// namespaceAbbreviation is one line of the --abbrev-namespace legend, e.g. "t-p" for "team-payments"
+type namespaceAbbreviation struct {
+	Short  string
+	Prefix string
+}
+
+// abbreviateNamespaces shortens the namespaces of items whose prefix (all dash separated segments but the last)
+// is shared by at least two namespaces, to the first letter of each segment: team-payments-prod -> t-p-prod
+// Prefixes that would get the same abbreviation are kept, so each abbreviation stands for a single prefix.
+// Returns a copy of the items and the abbreviations used, sorted
+func abbreviateNamespaces(items []OutputItem) ([]OutputItem, []namespaceAbbreviation) {
+	namespacePrefix := func(namespace string) string {
+		if i := strings.LastIndex(namespace, "-"); i > 0 {
+			return namespace[:i]
+		}
+		return ""
+	}
+
+	// Count the distinct namespaces of each prefix
+	namespacesByPrefix := make(map[string]map[string]bool)
+	for _, item := range items {
+		prefix := namespacePrefix(item.Namespace)
+		if prefix == "" {
+			continue
+		}
+		if namespacesByPrefix[prefix] == nil {
+			namespacesByPrefix[prefix] = make(map[string]bool)
+		}
+		namespacesByPrefix[prefix][item.Namespace] = true
+	}
+
+	prefixesByShort := make(map[string][]string)
+	for prefix, namespaces := range namespacesByPrefix {
+		if len(namespaces) < 2 {
+			continue
+		}
+		var short []string
+		for _, segment := range strings.Split(prefix, "-") {
+			if r, _ := utf8.DecodeRuneInString(segment); r != utf8.RuneError {
+				segment = string(r)
+			}
+			short = append(short, segment)
+		}
+		if abbreviation := strings.Join(short, "-"); abbreviation != prefix {
+			prefixesByShort[abbreviation] = append(prefixesByShort[abbreviation], prefix)
+		}
+	}
+
+	shortByPrefix := make(map[string]string)
+	var legend []namespaceAbbreviation
+	for short, prefixes := range prefixesByShort {
+		if len(prefixes) == 1 {
+			shortByPrefix[prefixes[0]] = short
+			legend = append(legend, namespaceAbbreviation{Short: short, Prefix: prefixes[0]})
+		}
+	}
+	sort.Slice(legend, func(i, j int) bool { return legend[i].Short < legend[j].Short })
+
+	abbreviated := make([]OutputItem, len(items))
+	for i, item := range items {
+		prefix := namespacePrefix(item.Namespace)
+		if short, ok := shortByPrefix[prefix]; ok {
+			item.Namespace = short + strings.TrimPrefix(item.Namespace, prefix)
+		}
+		abbreviated[i] = item
+	}
+
+	return abbreviated, legend
+}
+
+// printNamespaceLegend explains the namespace prefixes abbreviated by --abbrev-namespace
+func printNamespaceLegend(w io.Writer, legend []namespaceAbbreviation) {
+	if len(legend) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nNamespace prefixes:\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, abbreviation := range legend {
+		fmt.Fprintf(tw, "  %s-\t%s-\n", abbreviation.Short, abbreviation.Prefix)
+	}
+	tw.Flush()
+}
+
+// groupItemsByNamespace sorts items by namespace and splits them into one group per namespace
+// The order of items within a namespace is preserved
+func groupItemsByNamespace(items []OutputItem) [][]OutputItem {
+	return groupItems(items, func(item OutputItem) string { return item.Namespace })
+}
+
+// groupItems sorts items by the given key and splits them into one group per key value
+// The order of items within a group is preserved
+func groupItems(items []OutputItem, key func(OutputItem) string) [][]OutputItem {
+	sorted := make([]OutputItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return key(sorted[i]) < key(sorted[j])
+	})
+
+	var groups [][]OutputItem
+	for i, item := range sorted {
+		if i == 0 || key(item) != key(sorted[i-1]) {
+			groups = append(groups, []OutputItem{})
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], item)
+	}
+
+	return groups
+}
+
+// printTableSection outputs a single table
+func printTableSection(output Output, cmdType string, subCommand string, namespaced bool, opts TableOptions) {
+	if !opts.Color || !term.IsTerminal(int(os.Stdout.Fd())) {
+		writeTable(os.Stdout, output, cmdType, subCommand, namespaced, opts)
+		return
+	}
+
+	// Colors are applied after alignment so escape codes don't break column widths
+	var buf bytes.Buffer
+	writeTable(&buf, output, cmdType, subCommand, namespaced, opts)
+	fmt.Print(colorizeTable(buf.String(), output, namespaced))
+}
+
+// summarizeLifecycleHandler renders a postStart or preStop handler on one line, e.g.
+// "exec:/bin/sh -c drain", "httpGet:8080/shutdown", "tcpSocket:8080" or "sleep:5s"
+func summarizeLifecycleHandler(handler map[string]interface{}) string {
+	if len(handler) == 0 {
+		return "<none>"
+	}
+
+	if command, found, _ := unstructured.NestedStringSlice(handler, "exec", "command"); found {
+		return "exec:" + strings.Join(command, " ")
+	}
+	if httpGet, found, _ := unstructured.NestedMap(handler, "httpGet"); found {
+		target := fmt.Sprintf("%v", httpGet["port"])
+		if host, ok := httpGet["host"].(string); ok && host != "" {
+			target = host + ":" + target
+		}
+		path, _ := httpGet["path"].(string)
+		return "httpGet:" + target + path
+	}
+	if tcpSocket, found, _ := unstructured.NestedMap(handler, "tcpSocket"); found {
+		return fmt.Sprintf("tcpSocket:%v", tcpSocket["port"])
+	}
+	if seconds, found, _ := unstructured.NestedFieldNoCopy(handler, "sleep", "seconds"); found {
+		return fmt.Sprintf("sleep:%vs", seconds)
+	}
+	return "present"
+}
+
+// formatPriorityCells renders the PRIORITYCLASS, VALUE and PREEMPTION cells of scheduling priority tables
+// Fields that aren't set in the pod spec show <none> (the API server fills them in for pods from the PriorityClass),
+// unless class was resolved (--resolve-priority), which then gives the value and preemption policy
+func formatPriorityCells(priority map[string]interface{}, class *PriorityClassInfo) string {
+	cells := []string{"<none>", "<none>", "<none>"}
+	keys := []string{"priorityClassName", "priority", "preemptionPolicy"}
+	for i, key := range keys {
+		if value, ok := priority[key]; ok {
+			cells[i] = fmt.Sprintf("%v", value)
+		}
+	}
+	if class == nil {
+		return strings.Join(cells, "\t")
+	}
+
+	switch {
+	case class.Missing:
+		cells[0] = class.Name + " (not found)"
+	case priority["priorityClassName"] == nil:
+		cells[0] = class.Name + " (globalDefault)"
+	}
+	if _, ok := priority["priority"]; !ok && class.Value != nil {
+		cells[1] = fmt.Sprintf("%d", *class.Value)
+	}
+	if _, ok := priority["preemptionPolicy"]; !ok && class.PreemptionPolicy != "" {
+		cells[2] = class.PreemptionPolicy
+	}
+	return strings.Join(cells, "\t")
+}
+
+// formatMergedResources renders the REQUESTS/LIMITS cell of scheduling resources tables (--table-layout=merged):
+// each resource as request/limit, e.g. "cpu: 500m/1, memory: 256Mi/512Mi", with - for the side that is unset.
+// Several containers are told apart by their name, the pod overhead (which has no limit) comes last.
+func formatMergedResources(resources []ContainerResources, overhead map[string]interface{}) string {
+	pair := func(requests, limits map[string]interface{}) string {
+		names := make(map[string]bool)
+		for name := range requests {
+			names[name] = true
+		}
+		for name := range limits {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+
+		cells := make([]string, 0, len(sorted))
+		for _, name := range sorted {
+			request, limit := "-", "-"
+			if value, ok := requests[name]; ok {
+				request = fmt.Sprintf("%v", value)
+			}
+			if value, ok := limits[name]; ok {
+				limit = fmt.Sprintf("%v", value)
+			}
+			cells = append(cells, fmt.Sprintf("%s: %s/%s", name, request, limit))
+		}
+		return strings.Join(cells, ", ")
+	}
+
+	var parts []string
+	for _, container := range resources {
+		merged := pair(container.Requests, container.Limits)
+		if merged == "" {
+			// Only resource claims
+			continue
+		}
+		if len(resources) > 1 {
+			merged = container.Name + " " + merged
+		}
+		parts = append(parts, merged)
+	}
+	if len(overhead) > 0 {
+		names := make([]string, 0, len(overhead))
+		for name := range overhead {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		cells := make([]string, 0, len(names))
+		for _, name := range names {
+			cells = append(cells, fmt.Sprintf("%s: %v", name, overhead[name]))
+		}
+		parts = append(parts, "overhead "+strings.Join(cells, ", "))
+	}
+	if len(parts) == 0 {
+		return "<none>"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatResourceClaims renders the CLAIMS cell of scheduling resources tables, the claim names joined by commas
+func formatResourceClaims(claims []interface{}) string {
+	var names []string
+	for _, claim := range claims {
+		if claimMap, ok := claim.(map[string]interface{}); ok {
+			if name, ok := claimMap["name"].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return "<none>"
+	}
+	return strings.Join(names, ",")
+}
+
+// formatRuntimeCells renders the RUNTIMECLASS, HOSTNET, HOSTPID and HOSTIPC cells of scheduling runtime tables
+// Host namespaces that aren't set are not shared, so they show false
+func formatRuntimeCells(runtime map[string]interface{}) string {
+	runtimeClass := "<none>"
+	if name, ok := runtime["runtimeClassName"].(string); ok {
+		runtimeClass = name
+	}
+	cells := []string{runtimeClass}
+	for _, key := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+		shared, _ := runtime[key].(bool)
+		cells = append(cells, fmt.Sprintf("%t", shared))
+	}
+	return strings.Join(cells, "\t")
+}
+
+// formatVolumeClaimTemplate renders the requested size, access modes and storage class of a claim template, e.g.
+// "10Gi ReadWriteOnce storageClass=fast-ssd", claims without a storage class use the cluster default
+func formatVolumeClaimTemplate(claim VolumeClaimTemplate) string {
+	var parts []string
+	if claim.Storage != "" {
+		parts = append(parts, claim.Storage)
+	}
+	if len(claim.AccessModes) > 0 {
+		parts = append(parts, strings.Join(claim.AccessModes, ","))
+	}
+	storageClass := claim.StorageClassName
+	if storageClass == "" {
+		storageClass = "<default>"
+	}
+	parts = append(parts, "storageClass="+storageClass)
+	return strings.Join(parts, " ")
+}
+
+// formatEnvFromRows renders an envFrom source as table rows (container, variable, value)
+// Unexpanded sources get a single row with the prefix followed by "*", expanded ones a row per variable
+func formatEnvFromRows(containerName string, source EnvFromSource) []string {
+	ref := "<configMapRef:" + source.Name + ">"
+	if source.Kind == "Secret" {
+		ref = "<secretRef:" + source.Name + ">"
+	}
+
+	if source.Missing {
+		if source.Optional {
+			return []string{containerName + "\t" + source.Prefix + "*\t" + ref + " not found (optional)"}
+		}
+		return []string{containerName + "\t" + source.Prefix + "*\t" + ref + " not found"}
+	}
+	if len(source.Variables) == 0 {
+		return []string{containerName + "\t" + source.Prefix + "*\t" + ref}
+	}
+
+	rows := make([]string, 0, len(source.Variables))
+	for _, variable := range source.Variables {
+		rows = append(rows, containerName+"\t"+variable+"\t"+ref)
+	}
+	return rows
+}
+
+// formatHostAliases renders host aliases like /etc/hosts entries on one line, e.g.
+// "10.0.0.5=db,db.internal 10.0.0.6=cache"
+func formatHostAliases(hostAliases []HostAlias) string {
+	entries := make([]string, 0, len(hostAliases))
+	for _, alias := range hostAliases {
+		entries = append(entries, alias.IP+"="+strings.Join(alias.Hostnames, ","))
+	}
+	return strings.Join(entries, " ")
+}
+
+// summarizeAffinity renders affinity rules on one line for wide tables, e.g.
+// "node: arch In [amd64]; podAnti: app=web topologyKey=hostname"
+// Preferred rules carry their weight ("node(50): ..."), rules of the same kind are separated by "; "
+func summarizeAffinity(affinity map[string]interface{}) string {
+	var parts []string
+
+	if nodeAffinity, ok := affinity["nodeAffinity"].(map[string]interface{}); ok {
+		required, _, _ := unstructured.NestedSlice(nodeAffinity, "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms")
+		for _, term := range required {
+			if termMap, ok := term.(map[string]interface{}); ok {
+				parts = append(parts, "node: "+formatNodeSelectorTerm(termMap))
+			}
+		}
+		preferred, _, _ := unstructured.NestedSlice(nodeAffinity, "preferredDuringSchedulingIgnoredDuringExecution")
+		for _, rule := range preferred {
+			ruleMap, ok := rule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			weight, _, _ := unstructured.NestedInt64(ruleMap, "weight")
+			preference, _, _ := unstructured.NestedMap(ruleMap, "preference")
+			parts = append(parts, fmt.Sprintf("node(%d): %s", weight, formatNodeSelectorTerm(preference)))
+		}
+	}
+
+	for _, kind := range []struct{ field, prefix string }{
+		{"podAffinity", "pod"},
+		{"podAntiAffinity", "podAnti"},
+	} {
+		podAffinity, ok := affinity[kind.field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		required, _, _ := unstructured.NestedSlice(podAffinity, "requiredDuringSchedulingIgnoredDuringExecution")
+		for _, term := range required {
+			if termMap, ok := term.(map[string]interface{}); ok {
+				parts = append(parts, kind.prefix+": "+formatPodAffinityTerm(termMap))
+			}
+		}
+		preferred, _, _ := unstructured.NestedSlice(podAffinity, "preferredDuringSchedulingIgnoredDuringExecution")
+		for _, rule := range preferred {
+			ruleMap, ok := rule.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			weight, _, _ := unstructured.NestedInt64(ruleMap, "weight")
+			term, _, _ := unstructured.NestedMap(ruleMap, "podAffinityTerm")
+			parts = append(parts, fmt.Sprintf("%s(%d): %s", kind.prefix, weight, formatPodAffinityTerm(term)))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "<none>"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatTopologySpreadConstraints renders each constraint as tab-separated maxSkew, topologyKey and
+// whenUnsatisfiable cells for wide tables, e.g. "1\ttopology.kubernetes.io/zone\tDoNotSchedule"
+func formatTopologySpreadConstraints(constraints []interface{}) []string {
+	var rows []string
+	for _, constraint := range constraints {
+		constraintMap, ok := constraint.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		maxSkew, _, _ := unstructured.NestedInt64(constraintMap, "maxSkew")
+		topologyKey, _, _ := unstructured.NestedString(constraintMap, "topologyKey")
+		// The API server defaults whenUnsatisfiable, manifests read with -F may not have it
+		whenUnsatisfiable, _, _ := unstructured.NestedString(constraintMap, "whenUnsatisfiable")
+		if whenUnsatisfiable == "" {
+			whenUnsatisfiable = "DoNotSchedule"
+		}
+		rows = append(rows, fmt.Sprintf("%d\t%s\t%s", maxSkew, topologyKey, whenUnsatisfiable))
+	}
+	return rows
+}
+
+// formatNodeSelectorTerm renders the matchExpressions and matchFields of a node selector term
+func formatNodeSelectorTerm(term map[string]interface{}) string {
+	var requirements []string
+	for _, field := range []string{"matchExpressions", "matchFields"} {
+		expressions, _, _ := unstructured.NestedSlice(term, field)
+		requirements = append(requirements, formatMatchExpressions(expressions)...)
+	}
+	return strings.Join(requirements, ",")
+}
+
+// formatPodAffinityTerm renders the label selector and topology key of a pod (anti-)affinity term
+func formatPodAffinityTerm(term map[string]interface{}) string {
+	var requirements []string
+
+	matchLabels, _, _ := unstructured.NestedStringMap(term, "labelSelector", "matchLabels")
+	keys := make([]string, 0, len(matchLabels))
+	for k := range matchLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		requirements = append(requirements, fmt.Sprintf("%s=%s", k, matchLabels[k]))
+	}
+	expressions, _, _ := unstructured.NestedSlice(term, "labelSelector", "matchExpressions")
+	requirements = append(requirements, formatMatchExpressions(expressions)...)
+
+	selector := strings.Join(requirements, ",")
+	if selector == "" {
+		selector = "<all>"
+	}
+	topologyKey, _, _ := unstructured.NestedString(term, "topologyKey")
+	return fmt.Sprintf("%s topologyKey=%s", selector, shortLabelKey(topologyKey))
+}
+
+// formatMatchExpressions renders match expressions as "key Op [values]", or "key Op" without values (Exists)
+func formatMatchExpressions(expressions []interface{}) []string {
+	var formatted []string
+	for _, expression := range expressions {
+		expressionMap, ok := expression.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _, _ := unstructured.NestedString(expressionMap, "key")
+		operator, _, _ := unstructured.NestedString(expressionMap, "operator")
+		values, _, _ := unstructured.NestedStringSlice(expressionMap, "values")
+		if len(values) == 0 {
+			formatted = append(formatted, fmt.Sprintf("%s %s", shortLabelKey(key), operator))
+		} else {
+			formatted = append(formatted, fmt.Sprintf("%s %s [%s]", shortLabelKey(key), operator, strings.Join(values, ",")))
+		}
+	}
+	return formatted
+}
+
+// shortLabelKey drops the prefix of well-known Kubernetes labels (kubernetes.io/arch -> arch)
+// so that summaries stay readable, other prefixes are kept
+func shortLabelKey(key string) string {
+	prefix, name, found := strings.Cut(key, "/")
+	if found && (prefix == "kubernetes.io" || strings.HasSuffix(prefix, ".kubernetes.io")) {
+		return name
+	}
+	return key
+}
+
+// formatRolloutRevision renders the REVISION cell of owner tables, e.g. "3 (current)" or "2 (previous, latest 3)"
+func formatRolloutRevision(rollout *RolloutRevisionInfo) string {
+	if rollout == nil {
+		return "<none>"
+	}
+	if rollout.Current {
+		return rollout.Revision + " (current)"
+	}
+	if rollout.LatestRevision == "" {
+		return rollout.Revision + " (unknown)"
+	}
+	return fmt.Sprintf("%s (previous, latest %s)", rollout.Revision, rollout.LatestRevision)
+}
+
+// colorizeTable adds subtle ANSI colors to an already aligned table:
+// names of resources younger than recentAge in yellow and <none> values dimmed
+func colorizeTable(table string, output Output, namespaced bool) string {
+	// Index recently created and stuck terminating resources by namespace/name (name only without NAMESPACE column)
+	recent := make(map[string]bool)
+	stuck := make(map[string]bool)
+	for _, item := range output.Items {
+		key := item.Name
+		if namespaced {
+			key = item.Namespace + "/" + item.Name
+		}
+		if isRecent(item) {
+			recent[key] = true
+		}
+		if item.Finalizers != nil && item.Finalizers.Stuck {
+			stuck[key] = true
+		}
+	}
+
+	lines := strings.Split(table, "\n")
+	for i, line := range lines {
+		// Skip header and separator
+		if i < 2 {
+			continue
+		}
+
+		line = strings.ReplaceAll(line, "<none>", dimColor+"<none>"+colorReset)
+
+		// Continuation rows (e.g. additional owners) start with blank cells
+		fields := strings.Fields(line)
+		if len(fields) > 0 && !strings.HasPrefix(line, " ") {
+			key := fields[0]
+			if namespaced && len(fields) > 1 {
+				key = fields[1] + "/" + fields[0]
+			}
+			if stuck[key] {
+				// The whole row, the finalizers are what the user has to act on
+				line = stuckColor + line + colorReset
+			} else if recent[key] {
+				line = recentColor + fields[0] + colorReset + strings.TrimPrefix(line, fields[0])
+			}
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// utf8BOM lets Excel on Windows detect UTF-8 in CSV/TSV files instead of assuming the system code page
+const utf8BOM = "\xEF\xBB\xBF"
+
+// printDelimited writes the table rows as CSV (comma ',') or TSV (comma '\t') records
+// The separator row is dropped and continuation rows keep their blank leading cells
+// excelCompat prefixes a UTF-8 BOM and ends records with CRLF
+func printDelimited(out io.Writer, output Output, cmdType string, subCommand string, namespaced bool, opts TableOptions, comma rune, excelCompat bool) error {
+	// Tabs and newlines inside values (e.g. multi-line annotations) would split cells and rows
+	output = escapeCellValues(output)
+
+	var buf bytes.Buffer
+	writeTableRows(&buf, output, cmdType, subCommand, namespaced, opts)
+
+	if excelCompat {
+		if _, err := io.WriteString(out, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(out)
+	writer.Comma = comma
+	writer.UseCRLF = excelCompat
+	for i, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if i == 1 {
+			continue
+		}
+		if err := writer.Write(strings.Split(line, "\t")); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCellValues returns a deep copy of output where every string has its tabs and newlines escaped
+// Any field may end up in a cell (a multi-line script in command args, an env value, an exec hook),
+// so the items are walked as a whole instead of listing the fields that can hold free text
+func escapeCellValues(output Output) Output {
+	replacer := strings.NewReplacer("\t", `\t`, "\r", `\r`, "\n", `\n`)
+	items := make([]OutputItem, len(output.Items))
+	for i, item := range output.Items {
+		items[i] = escapeStrings(reflect.ValueOf(item), replacer).Interface().(OutputItem)
+	}
+	output.Items = items
+	return output
+}
+
+// escapeStrings returns a deep copy of v with replacer applied to every string it holds
+// Map keys are kept as they are, label and annotation keys can't contain tabs or newlines
+func escapeStrings(v reflect.Value, replacer *strings.Replacer) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		escaped := reflect.New(v.Type()).Elem()
+		escaped.SetString(replacer.Replace(v.String()))
+		return escaped
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		escaped := reflect.New(v.Type().Elem())
+		escaped.Elem().Set(escapeStrings(v.Elem(), replacer))
+		return escaped
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		escaped := reflect.New(v.Type()).Elem()
+		escaped.Set(escapeStrings(v.Elem(), replacer))
+		return escaped
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		escaped := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			escaped.Index(i).Set(escapeStrings(v.Index(i), replacer))
+		}
+		return escaped
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		escaped := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			escaped.SetMapIndex(iter.Key(), escapeStrings(iter.Value(), replacer))
+		}
+		return escaped
+	case reflect.Struct:
+		// Unexported fields (e.g. of time.Time) are copied as they are
+		escaped := reflect.New(v.Type()).Elem()
+		escaped.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if escaped.Field(i).CanSet() {
+				escaped.Field(i).Set(escapeStrings(v.Field(i), replacer))
+			}
+		}
+		return escaped
+	}
+	return v
+}
+
+// writeTable writes the data in table format to out
+func writeTable(out io.Writer, output Output, cmdType string, subCommand string, namespaced bool, opts TableOptions) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
+	if opts.MaxColWidth <= 0 {
+		writeTableRows(w, output, cmdType, subCommand, namespaced, opts)
+		return
+	}
+
+	// Names are kept whole so rows stay identifiable (and colorizeTable can match them)
+	keep := 1
+	if namespaced {
+		keep = 2
+	}
+	var buf bytes.Buffer
+	writeTableRows(&buf, output, cmdType, subCommand, namespaced, opts)
+	for row, line := range strings.SplitAfter(buf.String(), "\n") {
+		cells := strings.Split(line, "\t")
+		for i := keep; i < len(cells); i++ {
+			cell := strings.TrimSuffix(cells[i], "\n")
+			shortened := truncateRunes(cell, opts.MaxColWidth)
+			if row == 1 && shortened != cell {
+				// The separator is only shortened, an ellipsis would look like a value
+				shortened = cell[:opts.MaxColWidth]
+			}
+			cells[i] = shortened + cells[i][len(cell):]
+		}
+		fmt.Fprint(w, strings.Join(cells, "\t"))
+	}
+}
+
+// truncateRunes shortens s to at most max characters, ending with an ellipsis when cut
+// Cuts on rune boundaries, slicing bytes would split multibyte characters (CJK, emoji) in annotation values
+func truncateRunes(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	if max == 1 {
+		return "…"
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
+
+// writeRowPrefix writes the name and namespace cells that start a table row
+// Resources listed over several rows (one per owner, container, volume...) only show them on the first row,
+// continuation rows get empty cells instead.
+func writeRowPrefix(w io.Writer, item OutputItem, namespaced, continuation bool) {
+	switch {
+	case continuation && namespaced:
+		fmt.Fprintf(w, "\t\t")
+	case continuation:
+		fmt.Fprintf(w, "\t")
+	case namespaced:
+		fmt.Fprintf(w, "%s\t%s\t", item.Name, item.Namespace)
+	default:
+		fmt.Fprintf(w, "%s\t", item.Name)
+	}
+}
+
+// writeTableRows writes the header, separator and item rows with tab-separated cells
+// writeTable aligns them, printDelimited turns them into CSV/TSV records
+func writeTableRows(w io.Writer, output Output, cmdType string, subCommand string, namespaced bool, opts TableOptions) {
 	// Print header
 	if namespaced {
 		fmt.Fprintf(w, "NAME\tNAMESPACE\t")
@@ -98,16 +1277,59 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 	}
 
 	// Determine column header based on cmdType
-	if cmdType == "labels" {
+	if cmdType == "labels" && len(opts.LabelColumns) > 0 {
+		headers := make([]string, len(opts.LabelColumns))
+		for i, key := range opts.LabelColumns {
+			headers[i] = strings.ToUpper(key)
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(headers, "\t"))
+	} else if cmdType == "labels" {
 		fmt.Fprintf(w, "LABELS\n")
 	} else if cmdType == "annotations" {
 		fmt.Fprintf(w, "ANNOTATIONS\n")
 	} else if cmdType == "owner" {
+		ownerKindHeader := "OWNER KIND"
+		if opts.FullGVK {
+			ownerKindHeader = "OWNER GVK"
+		}
+		revisionHeader := ""
+		if opts.Wide {
+			revisionHeader += "\tCONTROLLER\tOWNER UID"
+		}
+		if opts.SinceRevision {
+			revisionHeader += "\tREVISION"
+		}
 		if namespaced {
-			fmt.Fprintf(w, "OWNER NAMESPACE\tOWNER KIND\tOWNER NAME\n")
+			fmt.Fprintf(w, "OWNER NAMESPACE\t%s\tOWNER NAME%s\n", ownerKindHeader, revisionHeader)
 		} else {
-			fmt.Fprintf(w, "OWNER KIND\tOWNER NAME\n")
+			fmt.Fprintf(w, "%s\tOWNER NAME%s\n", ownerKindHeader, revisionHeader)
 		}
+	} else if cmdType == "pdb" {
+		fmt.Fprintf(w, "PDB\tMIN AVAILABLE\tMAX UNAVAILABLE\tALLOWED DISRUPTIONS\n")
+	} else if cmdType == "command" {
+		fmt.Fprintf(w, "CONTAINER\tCOMMAND\n")
+	} else if cmdType == "hooks" {
+		fmt.Fprintf(w, "CONTAINER\tPOSTSTART\tPRESTOP\n")
+	} else if cmdType == "volumes" {
+		fmt.Fprintf(w, "VOLUME\tTYPE\tSOURCE\n")
+	} else if cmdType == "readiness" {
+		fmt.Fprintf(w, "GATE\tSTATUS\n")
+	} else if cmdType == "env" {
+		fmt.Fprintf(w, "CONTAINER\tVARIABLE\tVALUE\n")
+	} else if cmdType == "lifecycle" {
+		fmt.Fprintf(w, "RESTARTPOLICY\tGRACEPERIOD\n")
+	} else if cmdType == "revision" {
+		fmt.Fprintf(w, "REVISION\tCHANGE-CAUSE\n")
+	} else if cmdType == "identity" {
+		fmt.Fprintf(w, "SERVICEACCOUNT\tAUTOMOUNT\tPULLSECRETS\n")
+	} else if cmdType == "replicas" {
+		fmt.Fprintf(w, "DESIRED\tCURRENT\tREADY\tAVAILABLE\n")
+	} else if cmdType == "service" {
+		fmt.Fprintf(w, "TYPE\tCLUSTER-IP\tSELECTOR\n")
+	} else if cmdType == "finalizers" {
+		fmt.Fprintf(w, "FINALIZERS\tDELETIONTIMESTAMP\n")
+	} else if cmdType == "network" {
+		fmt.Fprintf(w, "DNSPOLICY\tHOSTALIASES\n")
 	} else if cmdType == "scheduling" {
 		if subCommand == "" {
 			// Show summary of all fields
@@ -122,13 +1344,21 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 			case "nodeselector":
 				fmt.Fprintf(w, "NODESELECTOR\n")
 			case "resources":
-				fmt.Fprintf(w, "RESOURCES\n")
+				if opts.Layout == "merged" {
+					fmt.Fprintf(w, "REQUESTS/LIMITS\tCLAIMS\n")
+				} else {
+					fmt.Fprintf(w, "RESOURCES\tCLAIMS\n")
+				}
 			case "topology":
-				fmt.Fprintf(w, "TOPOLOGY SPREAD CONSTRAINTS\n")
+				if opts.Wide {
+					fmt.Fprintf(w, "MAX SKEW\tTOPOLOGY KEY\tWHEN UNSATISFIABLE\n")
+				} else {
+					fmt.Fprintf(w, "TOPOLOGY SPREAD CONSTRAINTS\n")
+				}
 			case "priority":
-				fmt.Fprintf(w, "PRIORITY\n")
+				fmt.Fprintf(w, "PRIORITYCLASS\tVALUE\tPREEMPTION\n")
 			case "runtime":
-				fmt.Fprintf(w, "RUNTIME\n")
+				fmt.Fprintf(w, "RUNTIMECLASS\tHOSTNET\tHOSTPID\tHOSTIPC\n")
 			}
 		}
 	}
@@ -139,15 +1369,62 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 	} else {
 		fmt.Fprintf(w, "----\t")
 	}
-	if cmdType == "owner" {
+	if cmdType == "labels" && len(opts.LabelColumns) > 0 {
+		separators := make([]string, len(opts.LabelColumns))
+		for i, key := range opts.LabelColumns {
+			separators[i] = strings.Repeat("-", len(key))
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(separators, "\t"))
+	} else if cmdType == "owner" {
+		revisionSeparator := ""
+		if opts.Wide {
+			revisionSeparator += "\t----------\t---------"
+		}
+		if opts.SinceRevision {
+			revisionSeparator += "\t--------"
+		}
 		if namespaced {
-			fmt.Fprintf(w, "---------------\t----------\t----------\n")
+			fmt.Fprintf(w, "---------------\t----------\t----------%s\n", revisionSeparator)
 		} else {
-			fmt.Fprintf(w, "----------\t----------\n")
+			fmt.Fprintf(w, "----------\t----------%s\n", revisionSeparator)
 		}
+	} else if cmdType == "pdb" {
+		fmt.Fprintf(w, "---\t-------------\t---------------\t-------------------\n")
+	} else if cmdType == "command" {
+		fmt.Fprintf(w, "---------\t-------\n")
+	} else if cmdType == "hooks" {
+		fmt.Fprintf(w, "---------\t---------\t-------\n")
+	} else if cmdType == "volumes" {
+		fmt.Fprintf(w, "------\t----\t------\n")
+	} else if cmdType == "readiness" {
+		fmt.Fprintf(w, "----\t------\n")
+	} else if cmdType == "env" {
+		fmt.Fprintf(w, "---------\t--------\t-----\n")
+	} else if cmdType == "lifecycle" {
+		fmt.Fprintf(w, "-------------\t-----------\n")
+	} else if cmdType == "revision" {
+		fmt.Fprintf(w, "--------\t------------\n")
+	} else if cmdType == "identity" {
+		fmt.Fprintf(w, "--------------\t---------\t-----------\n")
+	} else if cmdType == "replicas" {
+		fmt.Fprintf(w, "-------\t-------\t-----\t---------\n")
+	} else if cmdType == "service" {
+		fmt.Fprintf(w, "----\t----------\t--------\n")
+	} else if cmdType == "finalizers" {
+		fmt.Fprintf(w, "----------\t-----------------\n")
+	} else if cmdType == "network" {
+		fmt.Fprintf(w, "---------\t-----------\n")
 	} else if cmdType == "scheduling" {
 		if subCommand == "" {
 			fmt.Fprintf(w, "-----------\t--------\t-----------\t---------\n")
+		} else if subCommand == "topology" && opts.Wide {
+			fmt.Fprintf(w, "--------\t------------\t------------------\n")
+		} else if subCommand == "priority" {
+			fmt.Fprintf(w, "-------------\t-----\t----------\n")
+		} else if subCommand == "runtime" {
+			fmt.Fprintf(w, "------------\t-------\t-------\t-------\n")
+		} else if subCommand == "resources" {
+			fmt.Fprintf(w, "---------\t------\n")
 		} else {
 			fmt.Fprintf(w, "--------\n")
 		}
@@ -159,28 +1436,38 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 	for _, item := range output.Items {
 		if cmdType == "owner" {
 			// Handle ownerReferences
+			revisionCell := ""
+			if opts.SinceRevision {
+				revisionCell = "\t" + formatRolloutRevision(item.Rollout)
+			}
 			if len(item.OwnerReferences) == 0 {
+				wideCells := ""
+				if opts.Wide {
+					wideCells = "\t<none>\t<none>"
+				}
+				writeRowPrefix(w, item, namespaced, false)
+				// Namespaced tables have an OWNER NAMESPACE column
 				if namespaced {
-					fmt.Fprintf(w, "%s\t%s\t<none>\t<none>\t<none>\n", item.Name, item.Namespace)
-				} else {
-					fmt.Fprintf(w, "%s\t<none>\t<none>\n", item.Name)
+					fmt.Fprintf(w, "<none>\t")
 				}
+				fmt.Fprintf(w, "<none>\t<none>%s%s\n", wideCells, revisionCell)
 			} else {
 				for i, ownerRef := range item.OwnerReferences {
-					if i == 0 {
-						// First owner reference - show resource name
-						if namespaced {
-							fmt.Fprintf(w, "%s\t%s\t", item.Name, item.Namespace)
-						} else {
-							fmt.Fprintf(w, "%s\t", item.Name)
-						}
-					} else {
-						// Additional owner references - show empty name/namespace
-						if namespaced {
-							fmt.Fprintf(w, "\t\t")
-						} else {
-							fmt.Fprintf(w, "\t")
+					writeRowPrefix(w, item, namespaced, i > 0)
+
+					// Kind alone is ambiguous across API groups
+					ownerKind := ownerRef.Kind
+					if opts.FullGVK && ownerRef.APIVersion != "" {
+						ownerKind = ownerRef.APIVersion + "/" + ownerRef.Kind
+					}
+
+					wideCells := ""
+					if opts.Wide {
+						ownerUID := ownerRef.UID
+						if ownerUID == "" {
+							ownerUID = "<none>"
 						}
+						wideCells = fmt.Sprintf("\t%t\t%s", ownerRef.Controller, ownerUID)
 					}
 
 					if namespaced {
@@ -188,18 +1475,253 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 						if ownerNamespace == "" {
 							ownerNamespace = "<none>"
 						}
-						fmt.Fprintf(w, "%s\t%s\t%s\n", ownerNamespace, ownerRef.Kind, ownerRef.Name)
+						fmt.Fprintf(w, "%s\t%s\t%s%s%s\n", ownerNamespace, ownerKind, ownerRef.Name, wideCells, revisionCell)
 					} else {
-						fmt.Fprintf(w, "%s\t%s\n", ownerRef.Kind, ownerRef.Name)
+						fmt.Fprintf(w, "%s\t%s%s%s\n", ownerKind, ownerRef.Name, wideCells, revisionCell)
+					}
+					// The revision is shown once, on the first owner row
+					if opts.SinceRevision {
+						revisionCell = "\t"
 					}
 				}
 			}
-		} else {
-			// Handle labels or annotations
-			if namespaced {
-				fmt.Fprintf(w, "%s\t%s\t", item.Name, item.Namespace)
+		} else if cmdType == "pdb" {
+			// Handle PodDisruptionBudgets
+			if len(item.PodDisruptionBudgets) == 0 {
+				writeRowPrefix(w, item, true, false)
+				fmt.Fprintf(w, "<none>\t<none>\t<none>\t<none>\n")
 			} else {
-				fmt.Fprintf(w, "%s\t", item.Name)
+				for i, pdb := range item.PodDisruptionBudgets {
+					// PDBs only exist in namespaces
+					writeRowPrefix(w, item, true, i > 0)
+
+					minAvailable := "N/A"
+					if pdb.MinAvailable != nil {
+						minAvailable = fmt.Sprintf("%v", pdb.MinAvailable)
+					}
+					maxUnavailable := "N/A"
+					if pdb.MaxUnavailable != nil {
+						maxUnavailable = fmt.Sprintf("%v", pdb.MaxUnavailable)
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", pdb.Name, minAvailable, maxUnavailable, pdb.DisruptionsAllowed)
+				}
+			}
+		} else if cmdType == "command" {
+			// Handle container commands, one row per container
+			if len(item.Commands) == 0 {
+				writeRowPrefix(w, item, namespaced, false)
+				fmt.Fprintf(w, "<none>\t<none>\n")
+			} else {
+				for i, container := range item.Commands {
+					writeRowPrefix(w, item, namespaced, i > 0)
+
+					containerName := container.Name
+					if container.Init {
+						containerName += " (init)"
+					}
+
+					// Containers without command/args run the image entrypoint
+					commandLine := strings.Join(append(append([]string{}, container.Command...), container.Args...), " ")
+					if commandLine == "" {
+						commandLine = "<image default>"
+					}
+					fmt.Fprintf(w, "%s\t%s\n", containerName, commandLine)
+				}
+			}
+		} else if cmdType == "hooks" {
+			// Handle container hooks, one row per container
+			if len(item.Hooks) == 0 {
+				writeRowPrefix(w, item, namespaced, false)
+				fmt.Fprintf(w, "<none>\t<none>\t<none>\n")
+			} else {
+				for i, container := range item.Hooks {
+					writeRowPrefix(w, item, namespaced, i > 0)
+
+					containerName := container.Name
+					if container.Init {
+						containerName += " (init)"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", containerName, summarizeLifecycleHandler(container.PostStart), summarizeLifecycleHandler(container.PreStop))
+				}
+			}
+		} else if cmdType == "volumes" {
+			// Handle volumes, one row per volume and per StatefulSet claim template
+			rows := make([]string, 0, len(item.Volumes)+len(item.VolumeClaimTemplates))
+			for _, volume := range item.Volumes {
+				source := volume.Source
+				if source == "" {
+					source = "<none>"
+				}
+				rows = append(rows, volume.Name+"\t"+volume.Type+"\t"+source)
+			}
+			for _, claim := range item.VolumeClaimTemplates {
+				rows = append(rows, claim.Name+"\tvolumeClaimTemplate\t"+formatVolumeClaimTemplate(claim))
+			}
+			if len(rows) == 0 {
+				rows = []string{"<none>\t<none>\t<none>"}
+			}
+
+			for i, row := range rows {
+				writeRowPrefix(w, item, namespaced, i > 0)
+				fmt.Fprintf(w, "%s\n", row)
+			}
+		} else if cmdType == "env" {
+			// Handle environment variables, one row per variable (or envFrom source when not expanded)
+			var rows []string
+			for _, container := range item.Env {
+				containerName := container.Name
+				if container.Init {
+					containerName += " (init)"
+				}
+				for _, envVar := range container.Env {
+					value := envVar.Value
+					if envVar.ValueFrom != "" {
+						value = "<" + envVar.ValueFrom + ">"
+					}
+					rows = append(rows, containerName+"\t"+envVar.Name+"\t"+value)
+				}
+				for _, source := range container.EnvFrom {
+					rows = append(rows, formatEnvFromRows(containerName, source)...)
+				}
+				if len(container.Env) == 0 && len(container.EnvFrom) == 0 {
+					rows = append(rows, containerName+"\t<none>\t<none>")
+				}
+			}
+			if len(rows) == 0 {
+				rows = []string{"<none>\t<none>\t<none>"}
+			}
+
+			for i, row := range rows {
+				writeRowPrefix(w, item, namespaced, i > 0)
+				fmt.Fprintf(w, "%s\n", row)
+			}
+		} else if cmdType == "readiness" {
+			// Handle readiness gates, one row per gate
+			rows := make([]string, 0, len(item.ReadinessGates))
+			for _, gate := range item.ReadinessGates {
+				status := gate.Status
+				if status == "" {
+					status = "<none>"
+				}
+				rows = append(rows, gate.ConditionType+"\t"+status)
+			}
+			if len(rows) == 0 {
+				rows = []string{"<none>\t<none>"}
+			}
+
+			for i, row := range rows {
+				writeRowPrefix(w, item, namespaced, i > 0)
+				fmt.Fprintf(w, "%s\n", row)
+			}
+		} else if cmdType == "lifecycle" {
+			// Handle lifecycle fields
+			restartPolicy := "<none>"
+			gracePeriod := "<none>"
+			if item.Lifecycle != nil {
+				if item.Lifecycle.RestartPolicy != "" {
+					restartPolicy = item.Lifecycle.RestartPolicy
+				}
+				if item.Lifecycle.TerminationGracePeriodSeconds != nil {
+					gracePeriod = fmt.Sprintf("%ds", *item.Lifecycle.TerminationGracePeriodSeconds)
+				}
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\t%s\n", restartPolicy, gracePeriod)
+		} else if cmdType == "revision" {
+			// Handle rollout revision annotations
+			revision := "<none>"
+			changeCause := "<none>"
+			if item.Revision != nil {
+				if item.Revision.Revision != "" {
+					revision = item.Revision.Revision
+				}
+				if item.Revision.ChangeCause != "" {
+					changeCause = item.Revision.ChangeCause
+				}
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\t%s\n", revision, changeCause)
+		} else if cmdType == "identity" {
+			// Handle service account and pull secrets
+			serviceAccount := "default"
+			automount := "<unset>"
+			pullSecrets := "<none>"
+			if item.Identity != nil {
+				serviceAccount = item.Identity.ServiceAccountName
+				if item.Identity.AutomountServiceAccountToken != nil {
+					automount = fmt.Sprintf("%t", *item.Identity.AutomountServiceAccountToken)
+				}
+				if len(item.Identity.ImagePullSecrets) > 0 {
+					pullSecrets = strings.Join(item.Identity.ImagePullSecrets, ",")
+				}
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\t%s\t%s\n", serviceAccount, automount, pullSecrets)
+		} else if cmdType == "replicas" {
+			// Handle replica counts, kinds without replicas show <none>
+			counts := "<none>\t<none>\t<none>\t<none>"
+			if item.Replicas != nil {
+				counts = fmt.Sprintf("%d\t%d\t%d\t%d", item.Replicas.Desired, item.Replicas.Current, item.Replicas.Ready, item.Replicas.Available)
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\n", counts)
+		} else if cmdType == "service" {
+			// Handle Service exposure, other kinds show <none>
+			fields := "<none>\t<none>\t<none>"
+			if item.Service != nil {
+				clusterIP := item.Service.ClusterIP
+				if clusterIP == "" {
+					clusterIP = "<none>"
+				}
+				// Services without a selector (e.g. ExternalName) route to manually managed endpoints
+				selector := "<none>"
+				if len(item.Service.Selector) > 0 {
+					selector = labels.Set(item.Service.Selector).String()
+				}
+				fields = fmt.Sprintf("%s\t%s\t%s", item.Service.Type, clusterIP, selector)
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\n", fields)
+		} else if cmdType == "finalizers" {
+			// Handle finalizers, resources not being deleted show <none>
+			finalizers := "<none>"
+			deletionTimestamp := "<none>"
+			if item.Finalizers != nil {
+				if len(item.Finalizers.Finalizers) > 0 {
+					finalizers = strings.Join(item.Finalizers.Finalizers, ",")
+				}
+				if item.Finalizers.DeletionTimestamp != "" {
+					deletionTimestamp = item.Finalizers.DeletionTimestamp
+				}
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\t%s\n", finalizers, deletionTimestamp)
+		} else if cmdType == "network" {
+			// Handle DNS policy and host aliases, kinds without a pod spec show <none>
+			dnsPolicy := "<none>"
+			hostAliases := "<none>"
+			if item.Network != nil {
+				dnsPolicy = item.Network.DNSPolicy
+				if len(item.Network.HostAliases) > 0 {
+					hostAliases = formatHostAliases(item.Network.HostAliases)
+				}
+			}
+			writeRowPrefix(w, item, namespaced, false)
+			fmt.Fprintf(w, "%s\t%s\n", dnsPolicy, hostAliases)
+		} else if cmdType != "scheduling" {
+			// Handle labels or annotations
+			writeRowPrefix(w, item, namespaced, false)
+
+			// One cell per requested label, blank when the resource doesn't have it
+			if cmdType == "labels" && len(opts.LabelColumns) > 0 {
+				values := make([]string, len(opts.LabelColumns))
+				if item.Labels != nil {
+					for i, key := range opts.LabelColumns {
+						values[i] = (*item.Labels)[key]
+					}
+				}
+				fmt.Fprintf(w, "%s\n", strings.Join(values, "\t"))
+				continue
 			}
 
 			// Format labels or annotations as key=value pairs
@@ -235,11 +1757,7 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 
 		if cmdType == "scheduling" {
 			// Handle scheduling
-			if namespaced {
-				fmt.Fprintf(w, "%s\t%s\t", item.Name, item.Namespace)
-			} else {
-				fmt.Fprintf(w, "%s\t", item.Name)
-			}
+			writeRowPrefix(w, item, namespaced, false)
 
 			if subCommand == "" {
 				// Show summary
@@ -257,7 +1775,9 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 						nodeSelectorStr = "<none>"
 					}
 
-					if len(item.Scheduling.Affinity) > 0 {
+					if len(item.Scheduling.Affinity) > 0 && opts.Wide {
+						affinityStr = summarizeAffinity(item.Scheduling.Affinity)
+					} else if len(item.Scheduling.Affinity) > 0 {
 						affinityStr = "present"
 					} else {
 						affinityStr = "<none>"
@@ -269,7 +1789,7 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 						tolerationsStr = "<none>"
 					}
 
-					if item.Scheduling.ResourceRequests != nil || item.Scheduling.ResourceLimits != nil {
+					if item.Scheduling.ResourceRequests != nil || item.Scheduling.ResourceLimits != nil || item.Scheduling.Overhead != nil {
 						resourcesStr = "present"
 					} else {
 						resourcesStr = "<none>"
@@ -293,7 +1813,9 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 						valueStr = "<none>"
 					}
 				case "affinity":
-					if len(item.Affinity) > 0 {
+					if len(item.Affinity) > 0 && opts.Wide {
+						valueStr = summarizeAffinity(item.Affinity)
+					} else if len(item.Affinity) > 0 {
 						valueStr = "present"
 					} else {
 						valueStr = "<none>"
@@ -310,33 +1832,47 @@ func printTable(output Output, cmdType string, subCommand string, namespaced boo
 						valueStr = "<none>"
 					}
 				case "resources":
-					if len(item.Resources) > 0 {
+					switch {
+					case opts.Layout == "merged":
+						valueStr = formatMergedResources(item.Resources, item.Overhead)
+					case len(item.Resources) > 0 && len(item.Overhead) > 0:
+						valueStr = fmt.Sprintf("%d container(s) + overhead", len(item.Resources))
+					case len(item.Resources) > 0:
 						valueStr = fmt.Sprintf("%d container(s)", len(item.Resources))
-					} else {
+					case len(item.Overhead) > 0:
+						valueStr = "overhead"
+					default:
 						valueStr = "<none>"
 					}
+					valueStr += "\t" + formatResourceClaims(item.ResourceClaims)
 				case "topology":
-					if len(item.TopologySpreadConstraints) > 0 {
+					if opts.Wide {
+						// One row per constraint, continuation rows leave name/namespace blank
+						rows := formatTopologySpreadConstraints(item.TopologySpreadConstraints)
+						if len(rows) == 0 {
+							rows = []string{"<none>\t<none>\t<none>"}
+						}
+						var b strings.Builder
+						for i, row := range rows {
+							if i > 0 {
+								b.WriteString("\n")
+								writeRowPrefix(&b, item, namespaced, true)
+							}
+							b.WriteString(row)
+						}
+						valueStr = b.String()
+					} else if len(item.TopologySpreadConstraints) > 0 {
 						valueStr = fmt.Sprintf("%d constraint(s)", len(item.TopologySpreadConstraints))
 					} else {
 						valueStr = "<none>"
 					}
 				case "priority":
-					if len(item.Priority) > 0 {
-						valueStr = "present"
-					} else {
-						valueStr = "<none>"
-					}
+					valueStr = formatPriorityCells(item.Priority, item.PriorityClass)
 				case "runtime":
-					if len(item.Runtime) > 0 {
-						valueStr = "present"
-					} else {
-						valueStr = "<none>"
-					}
+					valueStr = formatRuntimeCells(item.Runtime)
 				}
 				fmt.Fprintf(w, "%s\n", valueStr)
 			}
 		}
 	}
 }
-
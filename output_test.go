@@ -0,0 +1,677 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// setNow pins the clock used for age calculations for the duration of a test
+func setNow(t *testing.T, fixed time.Time) {
+	t.Helper()
+	previous := now
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = previous })
+}
+
+func TestItemAgeUsesOverriddenNow(t *testing.T) {
+	fixed := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, fixed)
+
+	tests := []struct {
+		name       string
+		created    time.Time
+		wantAge    time.Duration
+		wantRecent bool
+	}{
+		{name: "unknown timestamp", created: time.Time{}, wantAge: 0, wantRecent: false},
+		{name: "just created", created: fixed.Add(-30 * time.Second), wantAge: 30 * time.Second, wantRecent: true},
+		{name: "at threshold", created: fixed.Add(-recentAge), wantAge: recentAge, wantRecent: false},
+		{name: "old", created: fixed.Add(-48 * time.Hour), wantAge: 48 * time.Hour, wantRecent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := OutputItem{Name: "web", CreationTimestamp: tt.created}
+			if got := itemAge(item); got != tt.wantAge {
+				t.Errorf("itemAge() = %v, want %v", got, tt.wantAge)
+			}
+			if got := isRecent(item); got != tt.wantRecent {
+				t.Errorf("isRecent() = %v, want %v", got, tt.wantRecent)
+			}
+		})
+	}
+}
+
+func TestColorizeTableHighlightsRecentItems(t *testing.T) {
+	fixed := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	setNow(t, fixed)
+
+	output := Output{Items: []OutputItem{
+		{Name: "new", CreationTimestamp: fixed.Add(-time.Minute)},
+		{Name: "old", CreationTimestamp: fixed.Add(-time.Hour)},
+	}}
+	table := "NAME   OWNER\n----   -----\nnew    <none>\nold    <none>\n"
+
+	got := colorizeTable(table, output, false)
+	want := "NAME   OWNER\n----   -----\n\033[33mnew\033[0m    \033[2m<none>\033[0m\nold    \033[2m<none>\033[0m\n"
+	if got != want {
+		t.Errorf("colorizeTable() = %q, want %q", got, want)
+	}
+}
+
+func TestColorizeTableHighlightsStuckTerminating(t *testing.T) {
+	setNow(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	output := Output{Items: []OutputItem{
+		{Name: "data-old", Namespace: "default", Finalizers: &FinalizersInfo{
+			Finalizers:        []string{"kubernetes.io/pvc-protection"},
+			DeletionTimestamp: "2024-01-01T11:00:00Z",
+			Stuck:             true,
+		}},
+		{Name: "data-0", Namespace: "default", Finalizers: &FinalizersInfo{Finalizers: []string{"kubernetes.io/pvc-protection"}}},
+	}}
+	table := "NAME      NAMESPACE  FINALIZERS\n----      ---------  ----------\n" +
+		"data-old  default    kubernetes.io/pvc-protection\ndata-0    default    kubernetes.io/pvc-protection\n"
+
+	got := colorizeTable(table, output, true)
+	want := "NAME      NAMESPACE  FINALIZERS\n----      ---------  ----------\n" +
+		"\033[31mdata-old  default    kubernetes.io/pvc-protection\033[0m\ndata-0    default    kubernetes.io/pvc-protection\n"
+	if got != want {
+		t.Errorf("colorizeTable() = %q, want %q", got, want)
+	}
+}
+
+// schedulingTemplateOutput returns scheduling output with tolerations and topology spread constraints
+// as the extractors produce them: []interface{} of unstructured maps
+func schedulingTemplateOutput() Output {
+	return Output{Items: []OutputItem{
+		{
+			Name:      "web",
+			Namespace: "default",
+			Scheduling: &SchedulingInfo{
+				Tolerations: []interface{}{
+					map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "web", "effect": "NoSchedule"},
+					map[string]interface{}{"key": "node.kubernetes.io/not-ready", "operator": "Exists"},
+				},
+				TopologySpreadConstraints: []interface{}{
+					map[string]interface{}{"maxSkew": int64(1), "topologyKey": "topology.kubernetes.io/zone"},
+				},
+			},
+		},
+		{
+			Name:      "api",
+			Namespace: "default",
+			Tolerations: []interface{}{
+				map[string]interface{}{"key": "gpu", "operator": "Exists"},
+			},
+		},
+	}}
+}
+
+func TestSplitOutputTemplate(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantFormat   string
+		wantTemplate string
+	}{
+		{input: "JSON", wantFormat: "json"},
+		{input: "jsonpath={.items[*].Name}", wantFormat: "jsonpath", wantTemplate: "{.items[*].Name}"},
+		{input: "Go-Template={{.items}}", wantFormat: "go-template", wantTemplate: "{{.items}}"},
+		{input: "table=x", wantFormat: "table=x"},
+	}
+
+	for _, tt := range tests {
+		format, template := splitOutputTemplate(tt.input)
+		if format != tt.wantFormat || template != tt.wantTemplate {
+			t.Errorf("splitOutputTemplate(%q) = (%q, %q), want (%q, %q)", tt.input, format, template, tt.wantFormat, tt.wantTemplate)
+		}
+	}
+}
+
+func TestPrintJSONPathRangesOverSchedulingFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "range over tolerations",
+			template: `{range .items[*]}{.scheduling.tolerations[*].key}{end}`,
+			want:     "dedicated node.kubernetes.io/not-ready",
+		},
+		{
+			name:     "nested range with names",
+			template: `{range .items[*]}{.name}:{range .scheduling.tolerations[*]} {.key}={.operator}{end}{"\n"}{end}`,
+			want:     "web: dedicated=Equal node.kubernetes.io/not-ready=Exists\napi:\n",
+		},
+		{
+			name:     "topology spread constraints",
+			template: `{.items[0].scheduling.topologySpreadConstraints[0].topologyKey}`,
+			want:     "topology.kubernetes.io/zone",
+		},
+		{
+			name:     "subcommand field without braces",
+			template: `.items[1].tolerations[*].key`,
+			want:     "gpu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printJSONPath(&buf, schedulingTemplateOutput(), tt.template); err != nil {
+				t.Fatalf("printJSONPath() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printJSONPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintGoTemplateRangesOverSchedulingFields(t *testing.T) {
+	template := `{{range .items}}{{.name}}{{if .scheduling}}{{range .scheduling.tolerations}} {{.key}}{{end}}` +
+		`{{range .scheduling.topologySpreadConstraints}} skew={{.maxSkew}}{{end}}{{end}};{{end}}`
+
+	var buf bytes.Buffer
+	if err := printGoTemplate(&buf, schedulingTemplateOutput(), template); err != nil {
+		t.Fatalf("printGoTemplate() error = %v", err)
+	}
+
+	want := "web dedicated node.kubernetes.io/not-ready skew=1;api;"
+	if got := buf.String(); got != want {
+		t.Errorf("printGoTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestPrintJSONPathInvalidTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printJSONPath(&buf, schedulingTemplateOutput(), "{.items[*"); err == nil {
+		t.Error("printJSONPath() error = nil, want a parse error")
+	}
+}
+
+func TestSummarizeAffinity(t *testing.T) {
+	affinity := map[string]interface{}{
+		"nodeAffinity": map[string]interface{}{
+			"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+				"nodeSelectorTerms": []interface{}{
+					map[string]interface{}{"matchExpressions": []interface{}{
+						map[string]interface{}{"key": "kubernetes.io/arch", "operator": "In", "values": []interface{}{"amd64"}},
+					}},
+				},
+			},
+		},
+		"podAntiAffinity": map[string]interface{}{
+			"preferredDuringSchedulingIgnoredDuringExecution": []interface{}{
+				map[string]interface{}{
+					"weight": int64(100),
+					"podAffinityTerm": map[string]interface{}{
+						"labelSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+						"topologyKey":   "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+
+	want := "node: arch In [amd64]; podAnti(100): app=web topologyKey=hostname"
+	if got := summarizeAffinity(affinity); got != want {
+		t.Errorf("summarizeAffinity() = %q, want %q", got, want)
+	}
+	if got := summarizeAffinity(map[string]interface{}{}); got != "<none>" {
+		t.Errorf("summarizeAffinity(empty) = %q, want <none>", got)
+	}
+}
+
+func TestPrintDelimited(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "front end"}
+	annotations := map[string]string{"note": "line one\nline two"}
+	commands := []ContainerCommand{{Name: "app", Command: []string{"sh", "-c"}, Args: []string{"set -e\necho hi\tthere"}}}
+	output := Output{Items: []OutputItem{{Name: "web", Namespace: "default", Labels: &labels, Annotations: &annotations, Commands: commands}}}
+
+	tests := []struct {
+		name        string
+		cmdType     string
+		comma       rune
+		excelCompat bool
+		want        string
+	}{
+		{
+			name:    "csv quotes cells with commas",
+			cmdType: "labels",
+			comma:   ',',
+			want:    "NAME,NAMESPACE,LABELS\nweb,default,\"app=web,tier=front end\"\n",
+		},
+		{
+			name:        "excel compatible tsv",
+			cmdType:     "labels",
+			comma:       '\t',
+			excelCompat: true,
+			want:        utf8BOM + "NAME\tNAMESPACE\tLABELS\r\nweb\tdefault\tapp=web,tier=front end\r\n",
+		},
+		{
+			name:    "newlines in values stay in one record",
+			cmdType: "annotations",
+			comma:   ',',
+			want:    "NAME,NAMESPACE,ANNOTATIONS\nweb,default,note=line one\\nline two\n",
+		},
+		{
+			name:    "multi-line args stay in one record",
+			cmdType: "command",
+			comma:   ',',
+			want:    "NAME,NAMESPACE,CONTAINER,COMMAND\nweb,default,app,sh -c set -e\\necho hi\\tthere\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := printDelimited(&buf, output, tt.cmdType, "", true, TableOptions{}, tt.comma, tt.excelCompat); err != nil {
+				t.Fatalf("printDelimited() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printDelimited() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The items are copied, the output printed in other formats keeps the real values
+	if got := output.Items[0].Commands[0].Args[0]; got != "set -e\necho hi\tthere" {
+		t.Errorf("printDelimited() changed the args to %q", got)
+	}
+}
+
+func TestFormatTopologySpreadConstraints(t *testing.T) {
+	constraints := []interface{}{
+		map[string]interface{}{"maxSkew": int64(1), "topologyKey": "topology.kubernetes.io/zone", "whenUnsatisfiable": "ScheduleAnyway"},
+		map[string]interface{}{"maxSkew": int64(2), "topologyKey": "kubernetes.io/hostname"},
+	}
+
+	want := []string{"1\ttopology.kubernetes.io/zone\tScheduleAnyway", "2\tkubernetes.io/hostname\tDoNotSchedule"}
+	if got := formatTopologySpreadConstraints(constraints); !equalStrings(got, want) {
+		t.Errorf("formatTopologySpreadConstraints() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatPriorityAndRuntimeCells(t *testing.T) {
+	priority := map[string]interface{}{"priorityClassName": "system-node-critical", "priority": int64(2000001000)}
+	if got, want := formatPriorityCells(priority, nil), "system-node-critical\t2000001000\t<none>"; got != want {
+		t.Errorf("formatPriorityCells() = %q, want %q", got, want)
+	}
+
+	// Templates only name the class, --resolve-priority fills in its value and policy
+	value := int64(1000)
+	class := &PriorityClassInfo{Name: "high", Value: &value, PreemptionPolicy: "Never"}
+	if got, want := formatPriorityCells(map[string]interface{}{"priorityClassName": "high"}, class), "high\t1000\tNever"; got != want {
+		t.Errorf("formatPriorityCells() with class = %q, want %q", got, want)
+	}
+	if got, want := formatPriorityCells(nil, &PriorityClassInfo{Name: "default", Value: &value, GlobalDefault: true}), "default (globalDefault)\t1000\t<none>"; got != want {
+		t.Errorf("formatPriorityCells() with globalDefault = %q, want %q", got, want)
+	}
+	if got, want := formatPriorityCells(map[string]interface{}{"priorityClassName": "gone"}, &PriorityClassInfo{Name: "gone", Missing: true}), "gone (not found)\t<none>\t<none>"; got != want {
+		t.Errorf("formatPriorityCells() with missing class = %q, want %q", got, want)
+	}
+
+	runtime := map[string]interface{}{"runtimeClassName": "gvisor", "hostPID": true}
+	if got, want := formatRuntimeCells(runtime), "gvisor\tfalse\ttrue\tfalse"; got != want {
+		t.Errorf("formatRuntimeCells() = %q, want %q", got, want)
+	}
+	if got, want := formatRuntimeCells(nil), "<none>\tfalse\tfalse\tfalse"; got != want {
+		t.Errorf("formatRuntimeCells(nil) = %q, want %q", got, want)
+	}
+}
+
+func TestOutputAsMap(t *testing.T) {
+	web := OutputItem{Name: "web", Namespace: "default", ResourceType: "pod"}
+	node := OutputItem{Name: "node-1", ResourceType: "node"}
+	got := outputAsMap(Output{Items: []OutputItem{web}})
+	if want := map[string]OutputItem{"default/web": web}; !reflect.DeepEqual(got, want) {
+		t.Errorf("outputAsMap() = %v, want %v", got, want)
+	}
+
+	// Same-named objects of different types get the type in front of their key
+	service := OutputItem{Name: "web", Namespace: "default", ResourceType: "service"}
+	got = outputAsMap(Output{Items: []OutputItem{web, service, node}})
+	want := map[string]OutputItem{"pod/default/web": web, "service/default/web": service, "node/node-1": node}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("outputAsMap() = %v, want %v", got, want)
+	}
+}
+
+func TestOutputEndsWithSingleNewline(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	output := Output{Items: []OutputItem{{Name: "web", Namespace: "default", ResourceType: "pod", Labels: &labels}}}
+
+	formats := map[string]func(w *bytes.Buffer) error{
+		"json":       func(w *bytes.Buffer) error { return writeJSON(w, output, false) },
+		"json color": func(w *bytes.Buffer) error { return writeJSON(w, output, true) },
+		"yaml":       func(w *bytes.Buffer) error { return writeYAML(w, output) },
+		"jsonl":      func(w *bytes.Buffer) error { return printJSONLines(w, output) },
+		"csv": func(w *bytes.Buffer) error {
+			return printDelimited(w, output, "labels", "", true, TableOptions{}, ',', false)
+		},
+		"table": func(w *bytes.Buffer) error {
+			writeTable(w, output, "labels", "", true, TableOptions{})
+			return nil
+		},
+		"name": func(w *bytes.Buffer) error {
+			printNames(w, output, "")
+			return nil
+		},
+	}
+
+	for name, write := range formats {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := write(&buf); err != nil {
+				t.Fatalf("write error = %v", err)
+			}
+			got := buf.Bytes()
+			if len(got) == 0 || got[len(got)-1] != '\n' {
+				t.Fatalf("output %q does not end with a newline", got)
+			}
+			if bytes.HasSuffix(got, []byte("\n\n")) {
+				t.Errorf("output %q ends with more than one newline", got)
+			}
+		})
+	}
+}
+
+func TestSummarizeLifecycleHandler(t *testing.T) {
+	tests := []struct {
+		handler map[string]interface{}
+		want    string
+	}{
+		{nil, "<none>"},
+		{map[string]interface{}{"exec": map[string]interface{}{"command": []interface{}{"/bin/sh", "-c", "drain"}}}, "exec:/bin/sh -c drain"},
+		{map[string]interface{}{"httpGet": map[string]interface{}{"path": "/shutdown", "port": int64(8080)}}, "httpGet:8080/shutdown"},
+		{map[string]interface{}{"httpGet": map[string]interface{}{"host": "localhost", "port": "admin"}}, "httpGet:localhost:admin"},
+		{map[string]interface{}{"tcpSocket": map[string]interface{}{"port": int64(9000)}}, "tcpSocket:9000"},
+		{map[string]interface{}{"sleep": map[string]interface{}{"seconds": int64(5)}}, "sleep:5s"},
+	}
+
+	for _, tt := range tests {
+		if got := summarizeLifecycleHandler(tt.handler); got != tt.want {
+			t.Errorf("summarizeLifecycleHandler(%v) = %q, want %q", tt.handler, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"description", 5, "desc…"},
+		{"説明文はここにあります", 5, "説明文は…"},
+		{"deploy 🚀🚀🚀 done", 9, "deploy 🚀…"},
+		{"🚀🚀", 1, "…"},
+		{"unlimited", 0, "unlimited"},
+	}
+
+	for _, tt := range tests {
+		got := truncateRunes(tt.s, tt.max)
+		if got != tt.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("truncateRunes(%q, %d) = %q is not valid UTF-8", tt.s, tt.max, got)
+		}
+	}
+}
+
+func TestWriteTableMaxColWidth(t *testing.T) {
+	annotations := map[string]string{"note": "日本語の説明"}
+	output := Output{Items: []OutputItem{{Name: "a-long-name", Namespace: "default", Annotations: &annotations}}}
+
+	var buf bytes.Buffer
+	writeTable(&buf, output, "annotations", "", true, TableOptions{MaxColWidth: 8})
+
+	want := "NAME         NAMESPACE  ANNOTAT…\n" +
+		"----         ---------  --------\n" +
+		"a-long-name  default    note=日本…\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTable() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTableReplicas(t *testing.T) {
+	output := Output{Items: []OutputItem{
+		{Name: "web", Namespace: "default", Replicas: &ReplicasInfo{Desired: 3, Current: 3, Ready: 2, Available: 2}},
+		{Name: "web-1", Namespace: "default"},
+	}}
+
+	var buf bytes.Buffer
+	writeTable(&buf, output, "replicas", "", true, TableOptions{})
+
+	want := "NAME   NAMESPACE  DESIRED  CURRENT  READY   AVAILABLE\n" +
+		"----   ---------  -------  -------  -----   ---------\n" +
+		"web    default    3        3        2       2\n" +
+		"web-1  default    <none>   <none>   <none>  <none>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTable() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTableService(t *testing.T) {
+	output := Output{Items: []OutputItem{
+		{Name: "web", Namespace: "default", Service: &ServiceInfo{Type: "ClusterIP", ClusterIP: "10.96.0.10", Selector: map[string]string{"tier": "frontend", "app": "web"}}},
+		{Name: "db", Namespace: "default", Service: &ServiceInfo{Type: "ExternalName"}},
+		{Name: "web-1", Namespace: "default"},
+	}}
+
+	var buf bytes.Buffer
+	writeTable(&buf, output, "service", "", true, TableOptions{})
+
+	want := "NAME   NAMESPACE  TYPE          CLUSTER-IP  SELECTOR\n" +
+		"----   ---------  ----          ----------  --------\n" +
+		"web    default    ClusterIP     10.96.0.10  app=web,tier=frontend\n" +
+		"db     default    ExternalName  <none>      <none>\n" +
+		"web-1  default    <none>        <none>      <none>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTable() = %q, want %q", got, want)
+	}
+}
+
+func TestPrintTableGroupByAnnotation(t *testing.T) {
+	output := Output{Items: []OutputItem{
+		{Name: "web", Namespace: "prod", AnnotationGroup: "payments"},
+		{Name: "debug", Namespace: "prod", AnnotationGroup: "<none>"},
+		{Name: "api", Namespace: "staging", AnnotationGroup: "checkout"},
+		{Name: "worker", Namespace: "staging", AnnotationGroup: "payments"},
+	}}
+
+	got := captureStdout(t, func() {
+		printTable(output, "revision", "", true, TableOptions{GroupByAnnotation: "owner-team"})
+	})
+
+	// Groups are sorted by value, the resources keep their order within a group
+	want := "owner-team: <none>\n" +
+		"NAME   NAMESPACE  REVISION  CHANGE-CAUSE\n" +
+		"----   ---------  --------  ------------\n" +
+		"debug  prod       <none>    <none>\n" +
+		"\n" +
+		"owner-team: checkout\n" +
+		"NAME  NAMESPACE  REVISION  CHANGE-CAUSE\n" +
+		"----  ---------  --------  ------------\n" +
+		"api   staging    <none>    <none>\n" +
+		"\n" +
+		"owner-team: payments\n" +
+		"NAME    NAMESPACE  REVISION  CHANGE-CAUSE\n" +
+		"----    ---------  --------  ------------\n" +
+		"web     prod       <none>    <none>\n" +
+		"worker  staging    <none>    <none>\n"
+	if got != want {
+		t.Errorf("printTable() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteTableLabelColumns(t *testing.T) {
+	web := map[string]string{"app": "web", "tier": "frontend", "version": "1.2"}
+	db := map[string]string{"app": "db"}
+	output := Output{Items: []OutputItem{{Name: "web", Labels: &web}, {Name: "db", Labels: &db}, {Name: "bare"}}}
+
+	var buf bytes.Buffer
+	writeTable(&buf, output, "labels", "", false, TableOptions{LabelColumns: []string{"app", "tier"}})
+
+	want := "NAME  APP  TIER\n" +
+		"----  ---  ----\n" +
+		"web   web  frontend\n" +
+		"db    db   \n" +
+		"bare       \n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeTable() = %q, want %q", got, want)
+	}
+}
+
+func TestAbbreviateNamespaces(t *testing.T) {
+	items := []OutputItem{
+		{Name: "api-1", Namespace: "team-payments-prod"},
+		{Name: "api-2", Namespace: "team-payments-staging"},
+		{Name: "web-1", Namespace: "tools-platform-prod"},
+		{Name: "web-2", Namespace: "tools-platform-dev"},
+		{Name: "db-1", Namespace: "team-orders-prod"},
+		{Name: "coredns", Namespace: "kube-system"},
+		{Name: "node", Namespace: ""},
+	}
+
+	got, legend := abbreviateNamespaces(items)
+
+	// team-payments and tools-platform would both become t-p, team-orders has a single namespace
+	wantNamespaces := []string{"team-payments-prod", "team-payments-staging", "tools-platform-prod", "tools-platform-dev", "team-orders-prod", "kube-system", ""}
+	for i, item := range got {
+		if item.Namespace != wantNamespaces[i] {
+			t.Errorf("item %d namespace = %q, want %q", i, item.Namespace, wantNamespaces[i])
+		}
+	}
+	if len(legend) != 0 {
+		t.Errorf("legend = %+v, want none", legend)
+	}
+
+	items = append(items, OutputItem{Name: "db-2", Namespace: "team-orders-staging"}, OutputItem{Name: "dns", Namespace: "kube-public"})
+	got, legend = abbreviateNamespaces(items)
+	if got[4].Namespace != "t-o-prod" || got[7].Namespace != "t-o-staging" || got[5].Namespace != "k-system" {
+		t.Errorf("namespaces = %q, %q, %q", got[4].Namespace, got[7].Namespace, got[5].Namespace)
+	}
+	wantLegend := []namespaceAbbreviation{{Short: "k", Prefix: "kube"}, {Short: "t-o", Prefix: "team-orders"}}
+	if !reflect.DeepEqual(legend, wantLegend) {
+		t.Errorf("legend = %+v, want %+v", legend, wantLegend)
+	}
+	if items[4].Namespace != "team-orders-prod" {
+		t.Errorf("input items were modified")
+	}
+
+	var buf bytes.Buffer
+	printNamespaceLegend(&buf, legend)
+	if want := "\nNamespace prefixes:\n  k-    kube-\n  t-o-  team-orders-\n"; buf.String() != want {
+		t.Errorf("legend output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteJSONSchemaVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, Output{SchemaVersion: outputSchemaVersion, Items: []OutputItem{}}, false); err != nil {
+		t.Fatalf("writeJSON() error: %v", err)
+	}
+	want := "{\n  \"schemaVersion\": \"getinfo.dev/v1\",\n  \"items\": []\n}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeJSON() = %q, want %q", got, want)
+	}
+
+	// Output without a version (e.g. snapshots) doesn't get an empty field
+	buf.Reset()
+	if err := writeYAML(&buf, Output{Items: []OutputItem{}}); err != nil {
+		t.Fatalf("writeYAML() error: %v", err)
+	}
+	if got := buf.String(); got != "items: []\n" {
+		t.Errorf("writeYAML() = %q, want %q", got, "items: []\n")
+	}
+}
+
+func TestOtelAttributes(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	annotations := map[string]string{"owner": "payments"}
+
+	tests := []struct {
+		name string
+		item OutputItem
+		want map[string]string
+	}{
+		{
+			name: "pod with labels",
+			item: OutputItem{Name: "web-1", Namespace: "prod", ResourceType: "pod", Labels: &labels},
+			want: map[string]string{"k8s.pod.name": "web-1", "k8s.namespace.name": "prod", "k8s.pod.label.app": "web"},
+		},
+		{
+			name: "deployment with annotations",
+			item: OutputItem{Name: "web", Namespace: "prod", ResourceType: "deployment.apps", Annotations: &annotations},
+			want: map[string]string{"k8s.deployment.name": "web", "k8s.namespace.name": "prod", "k8s.deployment.annotation.owner": "payments"},
+		},
+		{
+			name: "cluster-scoped node",
+			item: OutputItem{Name: "worker-1", ResourceType: "node"},
+			want: map[string]string{"k8s.node.name": "worker-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := otelAttributes(tt.item); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("otelAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatMergedResources(t *testing.T) {
+	app := ContainerResources{
+		Name:     "app",
+		Requests: map[string]interface{}{"cpu": "500m", "memory": "256Mi"},
+		Limits:   map[string]interface{}{"cpu": "1", "memory": "512Mi"},
+	}
+	sidecar := ContainerResources{Name: "proxy", Requests: map[string]interface{}{"cpu": "10m"}}
+	claimsOnly := ContainerResources{Name: "trainer", Claims: []interface{}{map[string]interface{}{"name": "gpu"}}}
+
+	tests := []struct {
+		name      string
+		resources []ContainerResources
+		overhead  map[string]interface{}
+		want      string
+	}{
+		{name: "single container", resources: []ContainerResources{app}, want: "cpu: 500m/1, memory: 256Mi/512Mi"},
+		{name: "unset limit", resources: []ContainerResources{sidecar}, want: "cpu: 10m/-"},
+		{name: "several containers", resources: []ContainerResources{app, sidecar}, want: "app cpu: 500m/1, memory: 256Mi/512Mi; proxy cpu: 10m/-"},
+		{name: "overhead", resources: []ContainerResources{sidecar}, overhead: map[string]interface{}{"cpu": "250m"}, want: "cpu: 10m/-; overhead cpu: 250m"},
+		{name: "claims only", resources: []ContainerResources{claimsOnly}, want: "<none>"},
+		{name: "nothing", want: "<none>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMergedResources(tt.resources, tt.overhead); got != tt.want {
+				t.Errorf("formatMergedResources() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintColorLegend(t *testing.T) {
+	var buf bytes.Buffer
+	printColorLegend(&buf, "json")
+	want := "Legend: \033[1;34mkeys\033[0m, \033[32mstrings\033[0m, \033[33mnumbers\033[0m, \033[1;33mbooleans\033[0m, \033[90mnull\033[0m\n"
+	if buf.String() != want {
+		t.Errorf("printColorLegend(json) = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	printColorLegend(&buf, "table")
+	want = "Legend: \033[33mcreated in the last 5 minutes\033[0m, \033[31mstuck terminating\033[0m, \033[2m<none>\033[0m\n"
+	if buf.String() != want {
+		t.Errorf("printColorLegend(table) = %q, want %q", buf.String(), want)
+	}
+}
@@ -2,40 +2,206 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 )
 
-// getGVR returns the GroupVersionResource for a given resource type
-// It uses the Kubernetes API discovery to resolve resource names, kinds, and short names
-func getGVR(resourceType string, config *rest.Config) (schema.GroupVersionResource, bool, error) {
+// resolveGVRs resolves resource types (names, kinds or short names) to GroupVersionResources
+// The API discovery runs once and every type is looked up in its result, so resolving many types
+// costs a single round of discovery requests. The same type given twice (pods,po) is returned once.
+// retryFailedGroups retries group versions that failed discovery (--include-unavailable-groups).
+func resolveGVRs(resourceTypes []string, config *rest.Config, retryFailedGroups bool) ([]resolvedResource, error) {
+	// Subresources (e.g., pods/log, deployments/scale) can't be queried, point to the parent resource
+	for _, resourceType := range resourceTypes {
+		if strings.Contains(resourceType, "/") {
+			parent := strings.SplitN(resourceType, "/", 2)[0]
+			return nil, fmt.Errorf("'%s' is a subresource, subresources are not supported. Use the parent resource instead: '%s'", resourceType, parent)
+		}
+	}
+
+	apiResourceLists, failedGroups, err := discoverAPIResources(config, retryFailedGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report every unknown type at once rather than one per run
+	var resolved []resolvedResource
+	var errs []error
+	seen := make(map[schema.GroupVersionResource]bool)
+	for _, resourceType := range resourceTypes {
+		resource, err := findAPIResource(apiResourceLists, failedGroups, resourceType)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[resource.GVR] {
+			continue
+		}
+		seen[resource.GVR] = true
+		resolved = append(resolved, resource)
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	return resolved, nil
+}
+
+// discoverAPIResources returns the API resources of every group version served by the cluster
+// Groups that fail discovery are skipped and returned with their error, the others are still usable
+// With retryFailedGroups, the failed groups are first retried one by one (see retryDiscoveryFailedGroups)
+func discoverAPIResources(config *rest.Config, retryFailedGroups bool) ([]*metav1.APIResourceList, []string, error) {
 	// Create discovery client to query API resources
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
-		return schema.GroupVersionResource{}, false, fmt.Errorf("error creating discovery client: %v", err)
+		return nil, nil, fmt.Errorf("error creating discovery client: %v", err)
 	}
 
 	// Get all API resources from the cluster
 	_, apiResourceLists, err := discoveryClient.ServerGroupsAndResources()
+	var failedGroups []string
 	if err != nil {
 		// Handle partial discovery errors (some groups may fail but others succeed)
 		if apiResourceLists == nil {
-			return schema.GroupVersionResource{}, false, fmt.Errorf("API discovery failed: %v", err)
+			return nil, nil, fmt.Errorf("%w: %w", errDiscoveryFailed, err)
 		}
-		// Continue with partial results
+		if retryFailedGroups {
+			var recovered []*metav1.APIResourceList
+			recovered, err = retryDiscoveryFailedGroups(config, err)
+			apiResourceLists = append(apiResourceLists, recovered...)
+		}
+	}
+	if err != nil {
+		// Continue with partial results, the failed groups are reported if a resource isn't found
+		failedGroups = discoveryFailedGroups(err)
+	}
+	return apiResourceLists, failedGroups, nil
+}
+
+// Retries of --include-unavailable-groups, short so one dead aggregated API doesn't stall the command
+const (
+	discoveryRetryAttempts = 3
+	discoveryRetryTimeout  = 5 * time.Second
+)
+
+// discoveryRetryDelay is the pause between two attempts, a variable so tests don't wait
+var discoveryRetryDelay = time.Second
+
+// retryDiscoveryFailedGroups asks again for the resources of each group version that failed a partial discovery
+// Aggregated API servers (metrics-server, CRDs served by an extension) often fail intermittently, a second
+// request on its own usually succeeds. Groups are retried concurrently, each attempt with a short timeout.
+// Returns the resources of the groups that answered and an error listing the groups that still fail (nil when none).
+func retryDiscoveryFailedGroups(config *rest.Config, err error) ([]*metav1.APIResourceList, error) {
+	var groupErr *discovery.ErrGroupDiscoveryFailed
+	if !errors.As(err, &groupErr) {
+		return nil, err
 	}
 
+	retryConfig := rest.CopyConfig(config)
+	retryConfig.Timeout = discoveryRetryTimeout
+	discoveryClient, clientErr := discovery.NewDiscoveryClientForConfig(retryConfig)
+	if clientErr != nil {
+		return nil, err
+	}
+	return retryGroupVersions(discoveryClient, groupErr.Groups)
+}
+
+// retryGroupVersions retries the discovery of the given group versions, see retryDiscoveryFailedGroups
+func retryGroupVersions(client discovery.ServerResourcesInterface, groups map[schema.GroupVersion]error) ([]*metav1.APIResourceList, error) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		recovered []*metav1.APIResourceList
+		failed    = make(map[schema.GroupVersion]error)
+	)
+	for gv, gvErr := range groups {
+		wg.Add(1)
+		go func(gv schema.GroupVersion, lastErr error) {
+			defer wg.Done()
+			for attempt := 1; attempt <= discoveryRetryAttempts; attempt++ {
+				apiResourceList, err := client.ServerResourcesForGroupVersion(gv.String())
+				if err == nil {
+					mu.Lock()
+					recovered = append(recovered, apiResourceList)
+					mu.Unlock()
+					return
+				}
+				lastErr = err
+				if attempt < discoveryRetryAttempts {
+					time.Sleep(discoveryRetryDelay)
+				}
+			}
+			mu.Lock()
+			failed[gv] = lastErr
+			mu.Unlock()
+		}(gv, gvErr)
+	}
+	wg.Wait()
+
+	// Keep the order of the other groups stable, lookups stop at the first match
+	sort.Slice(recovered, func(i, j int) bool { return recovered[i].GroupVersion < recovered[j].GroupVersion })
+	if len(failed) > 0 {
+		return recovered, &discovery.ErrGroupDiscoveryFailed{Groups: failed}
+	}
+	return recovered, nil
+}
+
+// findAPIResource looks up a resource type in the discovered API resources
+// The type matches a resource name (pods), a kind (Pod) or a short name (po), case-insensitively.
+// As a last resort, the singular or plural of the type is tried against the resource names, for CRDs
+// whose kind isn't the singular of their name and that define no short names (e.g. "proxy" for "proxies").
+func findAPIResource(apiResourceLists []*metav1.APIResourceList, failedGroups []string, resourceType string) (resolvedResource, error) {
 	// Normalize resource type for comparison (case-insensitive)
 	resourceTypeLower := strings.ToLower(resourceType)
 
+	if resource, ok := matchAPIResource(apiResourceLists, resourceTypeLower, true); ok {
+		return resource, nil
+	}
+	for _, guess := range pluralGuesses(resourceTypeLower) {
+		if resource, ok := matchAPIResource(apiResourceLists, guess, false); ok {
+			return resource, nil
+		}
+	}
+
+	return resolvedResource{}, &resourceTypeNotFoundError{resourceType: resourceType, failedGroups: failedGroups}
+}
+
+// pluralGuesses returns naive singular and plural forms of a lowercase resource type (strip or append s, es, ies)
+func pluralGuesses(resourceType string) []string {
+	var guesses []string
+	if strings.HasSuffix(resourceType, "ies") {
+		guesses = append(guesses, strings.TrimSuffix(resourceType, "ies")+"y")
+	}
+	if strings.HasSuffix(resourceType, "es") {
+		guesses = append(guesses, strings.TrimSuffix(resourceType, "es"))
+	}
+	if strings.HasSuffix(resourceType, "s") {
+		guesses = append(guesses, strings.TrimSuffix(resourceType, "s"))
+	}
+	if strings.HasSuffix(resourceType, "y") {
+		guesses = append(guesses, strings.TrimSuffix(resourceType, "y")+"ies")
+	}
+	return append(guesses, resourceType+"s", resourceType+"es")
+}
+
+// matchAPIResource returns the first discovered resource matching a lowercase resource type
+// Without aliases only the resource name is compared, not the kind, short names or full name
+func matchAPIResource(apiResourceLists []*metav1.APIResourceList, resourceTypeLower string, aliases bool) (resolvedResource, bool) {
 	// Search for the resource type across all API groups
 	for _, apiResourceList := range apiResourceLists {
 		if apiResourceList == nil {
@@ -52,13 +218,22 @@ func getGVR(resourceType string, config *rest.Config) (schema.GroupVersionResour
 			// 1. Resource name (e.g., "pods", "deployments")
 			// 2. Kind (e.g., "Pod", "Deployment")
 			// 3. Short names (e.g., "po", "deploy", "svc")
+			// 4. Full name with the group (e.g., "deployments.apps"), for names shadowed by a subcommand
 			resourceNameLower := strings.ToLower(apiResource.Name)
 			kindLower := strings.ToLower(apiResource.Kind)
 
-			matched := resourceNameLower == resourceTypeLower || kindLower == resourceTypeLower
+			matched := resourceNameLower == resourceTypeLower
+			if !matched && aliases {
+				matched = kindLower == resourceTypeLower
+			}
+			if !matched && aliases {
+				if gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion); err == nil && gv.Group != "" {
+					matched = resourceNameLower+"."+gv.Group == resourceTypeLower
+				}
+			}
 
 			// Check short names if not matched yet
-			if !matched {
+			if !matched && aliases {
 				for _, shortName := range apiResource.ShortNames {
 					if strings.ToLower(shortName) == resourceTypeLower {
 						matched = true
@@ -74,52 +249,385 @@ func getGVR(resourceType string, config *rest.Config) (schema.GroupVersionResour
 					continue
 				}
 
-				gvr := schema.GroupVersionResource{
-					Group:    gv.Group,
-					Version:  gv.Version,
-					Resource: apiResource.Name,
-				}
+				return resolvedResource{
+					GVR:        gv.WithResource(apiResource.Name),
+					Kind:       apiResource.Kind,
+					Namespaced: apiResource.Namespaced,
+				}, true
+			}
+		}
+	}
+
+	return resolvedResource{}, false
+}
+
+// allResourceTypes is the resource type argument that expands to every listable resource type of the cluster
+const allResourceTypes = "all"
+
+// resolvedResource is a resource type resolved through API discovery
+type resolvedResource struct {
+	GVR        schema.GroupVersionResource
+	Kind       string
+	Namespaced bool
+}
+
+// resolveResourceTypes resolves the resource type argument: a single type, a comma-separated list
+// (e.g. pods,deployments) or "all", which expands to every listable resource type within scope
+// Returns the resolved types and, for "all", the group versions whose discovery failed
+func resolveResourceTypes(resourceTypes string, scope string, config *rest.Config, retryFailedGroups bool) ([]resolvedResource, []string, error) {
+	if resourceTypes == allResourceTypes {
+		return discoverAllResources(scope, config, retryFailedGroups)
+	}
+
+	var types []string
+	for _, resourceType := range strings.Split(resourceTypes, ",") {
+		resourceType = strings.TrimSpace(resourceType)
+		if resourceType == "" {
+			return nil, nil, fmt.Errorf("empty resource type in '%s'", resourceTypes)
+		}
+		types = append(types, resourceType)
+	}
+
+	resolved, err := resolveGVRs(types, config, retryFailedGroups)
+	return resolved, nil, err
+}
+
+// resolveShadowedSubcommand tells whether a scheduling subcommand name (e.g. "priority") is also
+// a resource type of the cluster, such as the short name of a CRD
+func resolveShadowedSubcommand(subCommand string, config *rest.Config, retryFailedGroups bool) (resolvedResource, bool) {
+	resolved, err := resolveGVRs([]string{subCommand}, config, retryFailedGroups)
+	if err != nil || len(resolved) == 0 {
+		return resolvedResource{}, false
+	}
+	return resolved[0], true
+}
+
+// Scopes of --scope, which limits the resource types "all" expands to
+const (
+	scopeNamespaced = "namespaced"
+	scopeCluster    = "cluster"
+	scopeAll        = "all"
+)
 
-				return gvr, apiResource.Namespaced, nil
+// discoverPreferredResources returns the API resources of every group, in the preferred version of the group only
+// Like discoverAPIResources, groups that fail discovery are skipped and returned, after a retry with retryFailedGroups
+func discoverPreferredResources(config *rest.Config, retryFailedGroups bool) ([]*metav1.APIResourceList, []string, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating discovery client: %v", err)
+	}
+
+	apiResourceLists, err := discoveryClient.ServerPreferredResources()
+	var failedGroups []string
+	if err != nil {
+		if apiResourceLists == nil {
+			return nil, nil, fmt.Errorf("%w: %w", errDiscoveryFailed, err)
+		}
+		if retryFailedGroups {
+			var recovered []*metav1.APIResourceList
+			recovered, err = retryDiscoveryFailedGroups(config, err)
+			apiResourceLists = appendMissingGroups(apiResourceLists, recovered)
+		}
+		if err != nil {
+			failedGroups = discoveryFailedGroups(err)
+		}
+	}
+	return apiResourceLists, failedGroups, nil
+}
+
+// discoverAllResources returns every resource type that can be listed, in the preferred version of its group
+// scope keeps only namespaced or cluster-scoped types, or all of them
+func discoverAllResources(scope string, config *rest.Config, retryFailedGroups bool) ([]resolvedResource, []string, error) {
+	apiResourceLists, failedGroups, err := discoverPreferredResources(config, retryFailedGroups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return listableResources(apiResourceLists, scope), failedGroups, nil
+}
+
+// servedAgain lists resource types that another group serves as well, they are left out of "all"
+// when the other group is discovered so their objects aren't listed twice
+var servedAgain = map[schema.GroupResource]schema.GroupResource{
+	// The same Event objects, through the newer events API
+	{Group: "events.k8s.io", Resource: "events"}: {Resource: "events"},
+}
+
+// listableResources returns the resource types of the discovered lists that can be listed within scope
+// Subresources, types without the list verb and types served again by another group are left out.
+func listableResources(apiResourceLists []*metav1.APIResourceList, scope string) []resolvedResource {
+	discovered := make(map[schema.GroupResource]bool)
+	for _, apiResourceList := range apiResourceLists {
+		if apiResourceList == nil {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion); err == nil {
+			for _, apiResource := range apiResourceList.APIResources {
+				discovered[gv.WithResource(apiResource.Name).GroupResource()] = true
+			}
+		}
+	}
+
+	var resolved []resolvedResource
+	for _, apiResourceList := range apiResourceLists {
+		if apiResourceList == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, apiResource := range apiResourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") || !containsVerb(apiResource.Verbs, "list") {
+				continue
+			}
+			if (scope == scopeNamespaced && !apiResource.Namespaced) || (scope == scopeCluster && apiResource.Namespaced) {
+				continue
+			}
+			gvr := gv.WithResource(apiResource.Name)
+			if original, ok := servedAgain[gvr.GroupResource()]; ok && discovered[original] {
+				continue
 			}
+			resolved = append(resolved, resolvedResource{
+				GVR:        gvr,
+				Kind:       apiResource.Kind,
+				Namespaced: apiResource.Namespaced,
+			})
 		}
 	}
+	return resolved
+}
 
-	return schema.GroupVersionResource{}, false, fmt.Errorf("resource type '%s' not found in cluster", resourceType)
+// isSkippableListError checks if listing a type failed on the API server side (forbidden, method not
+// allowed, service unavailable...), a scan of all types skips such types instead of failing.
+// Errors that don't come from the server, such as a lost connection, are not skippable.
+func isSkippableListError(err error) bool {
+	errs := []error{err}
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		errs = aggregate.Errors()
+	}
+	for _, err := range errs {
+		var status apierrors.APIStatus
+		if !errors.Is(err, errForbidden) && !errors.As(err, &status) {
+			return false
+		}
+	}
+	return true
+}
+
+// listErrorMessage returns the message of the API server for a failed list, like discoveryFailedGroups does
+// for failed group versions. An aggregate of several namespaces reports its first error.
+func listErrorMessage(err error) string {
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) && len(aggregate.Errors()) > 0 {
+		err = aggregate.Errors()[0]
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Message != "" {
+		return status.Status().Message
+	}
+	return err.Error()
+}
+
+// appendMissingGroups adds the recovered resource lists of groups that have no version in lists yet
+// A group whose preferred version was discovered must not get its other versions listed a second time
+func appendMissingGroups(lists []*metav1.APIResourceList, recovered []*metav1.APIResourceList) []*metav1.APIResourceList {
+	groups := make(map[string]bool)
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(list.GroupVersion); err == nil {
+			groups[gv.Group] = true
+		}
+	}
+	for _, list := range recovered {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || groups[gv.Group] {
+			continue
+		}
+		groups[gv.Group] = true
+		lists = append(lists, list)
+	}
+	return lists
+}
+
+// containsVerb checks if an API resource supports a verb
+func containsVerb(verbs metav1.Verbs, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// errDiscoveryFailed marks errors of the API discovery, usually because the cluster can't be reached
+var errDiscoveryFailed = errors.New("API discovery failed")
+
+// resourceTypeNotFoundError is returned by findAPIResource when no API group serves the resource type
+type resourceTypeNotFoundError struct {
+	resourceType string
+	// Group versions whose discovery failed, one of them may serve the resource
+	failedGroups []string
+}
+
+func (e *resourceTypeNotFoundError) Error() string {
+	// A broken aggregated API (e.g. metrics-server down) hides the resources it serves
+	if len(e.failedGroups) > 0 {
+		return fmt.Sprintf("resource type '%s' not found in cluster. Discovery failed for %s, the resource may be served by one of them (check: kubectl get apiservices)",
+			e.resourceType, strings.Join(e.failedGroups, ", "))
+	}
+	return fmt.Sprintf("resource type '%s' not found in cluster", e.resourceType)
+}
+
+// discoveryFailedGroups returns the sorted group versions, with their error, that failed during a partial discovery
+func discoveryFailedGroups(err error) []string {
+	var groupErr *discovery.ErrGroupDiscoveryFailed
+	if !errors.As(err, &groupErr) {
+		return []string{fmt.Sprintf("some API groups (%v)", err)}
+	}
+
+	failed := make([]string, 0, len(groupErr.Groups))
+	for gv, gvErr := range groupErr.Groups {
+		failed = append(failed, fmt.Sprintf("%s (%v)", gv.String(), gvErr))
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// resourceClient is the part of the Kubernetes API used by getResources
+// It is implemented with the dynamic client (full objects) and the metadata client (metadata only)
+type resourceClient interface {
+	get(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error)
+	list(ctx context.Context, gvr schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions) ([]unstructured.Unstructured, error)
+}
+
+// dynamicResourceClient returns full objects, needed by commands that read the spec
+type dynamicResourceClient struct {
+	client dynamic.Interface
+}
+
+func (c dynamicResourceClient) get(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	return c.client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c dynamicResourceClient) list(ctx context.Context, gvr schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	list, err := c.client.Resource(gvr).Namespace(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// metadataResourceClient asks the API server for PartialObjectMetadata, so only metadata is transferred
+// This is much smaller than full objects on large clusters and enough for labels, annotations and owners
+type metadataResourceClient struct {
+	client metadata.Interface
+}
+
+func (c metadataResourceClient) get(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	item, err := c.client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	object := partialMetadataToUnstructured(*item)
+	return &object, nil
+}
+
+func (c metadataResourceClient) list(ctx context.Context, gvr schema.GroupVersionResource, namespace string, listOptions metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	list, err := c.client.Resource(gvr).Namespace(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]unstructured.Unstructured, 0, len(list.Items))
+	for _, item := range list.Items {
+		items = append(items, partialMetadataToUnstructured(item))
+	}
+	return items, nil
+}
+
+// partialMetadataToUnstructured converts a metadata-only object so it goes through the same extractors
+// apiVersion and kind are left empty (the server reports them as meta.k8s.io/v1 PartialObjectMetadata),
+// the caller fills them in from discovery
+func partialMetadataToUnstructured(item metav1.PartialObjectMetadata) unstructured.Unstructured {
+	object := unstructured.Unstructured{Object: map[string]interface{}{}}
+	object.SetName(item.Name)
+	object.SetNamespace(item.Namespace)
+	object.SetCreationTimestamp(item.CreationTimestamp)
+	object.SetLabels(item.Labels)
+	object.SetAnnotations(item.Annotations)
+	object.SetOwnerReferences(item.OwnerReferences)
+	object.SetManagedFields(item.ManagedFields)
+	object.SetFinalizers(item.Finalizers)
+	object.SetDeletionTimestamp(item.DeletionTimestamp)
+	return object
+}
+
+// trimResourceNames trims surrounding whitespace from the requested names
+// Kubernetes names are case-sensitive and are otherwise kept as is, a name left empty is an error
+func trimResourceNames(names []string) ([]string, error) {
+	trimmed := make([]string, 0, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("resource name #%d is empty", i+1)
+		}
+		trimmed = append(trimmed, name)
+	}
+	return trimmed, nil
 }
 
 // getResources retrieves resources from the Kubernetes API
+// When listing across all namespaces is forbidden, the accessible namespaces are listed one by one
+// and the names of the denied namespaces are returned
 func getResources(
-	client dynamic.Interface,
+	client resourceClient,
 	gvr schema.GroupVersionResource,
 	namespaced bool,
 	namespace string,
 	resourceNames []string,
 	labelSelector labels.Selector,
-) ([]unstructured.Unstructured, error) {
+	fieldSelector string,
+	fromCache bool,
+) ([]unstructured.Unstructured, []string, error) {
 	ctx := context.Background()
 
-	var resourceInterface dynamic.ResourceInterface
-	if namespaced {
-		if namespace == "" {
-			resourceInterface = client.Resource(gvr)
-		} else {
-			resourceInterface = client.Resource(gvr).Namespace(namespace)
-		}
-	} else {
-		resourceInterface = client.Resource(gvr)
+	// Cluster-scoped resources are queried without a namespace
+	if !namespaced {
+		namespace = ""
 	}
 
 	var items []unstructured.Unstructured
 
 	// If specific resource names are provided, get them individually
+	// Each result is stored at the index of its name so the output follows the command line order
+	// Every name is tried, so all the missing and forbidden ones are reported together
 	if len(resourceNames) > 0 {
-		for _, name := range resourceNames {
-			item, err := resourceInterface.Get(ctx, name, metav1.GetOptions{})
+		items = make([]unstructured.Unstructured, len(resourceNames))
+		var errs []error
+		// RBAC rules can grant get on some names only (resourceNames), the denied names share one error
+		var forbiddenNames []string
+		for i, name := range resourceNames {
+			item, err := client.get(ctx, gvr, namespace, name)
 			if err != nil {
-				return nil, fmt.Errorf("error getting %s: %v", name, err)
+				if apierrors.IsForbidden(err) {
+					forbiddenNames = append(forbiddenNames, name)
+					continue
+				}
+				errs = append(errs, fmt.Errorf("error getting %s: %w", name, err))
+				continue
 			}
-			items = append(items, *item)
+			items[i] = *item
+		}
+		if len(forbiddenNames) > 0 {
+			errs = append(errs, fmt.Errorf("error getting %s: %w", strings.Join(forbiddenNames, ", "), forbiddenError("get", gvr, namespace)))
+		}
+		if len(errs) > 0 {
+			return nil, nil, utilerrors.NewAggregate(errs)
 		}
 	} else {
 		// List all resources
@@ -127,14 +635,554 @@ func getResources(
 		if labelSelector != nil {
 			listOptions.LabelSelector = labelSelector.String()
 		}
+		// The field selector is passed verbatim, it was validated (or not, for --raw-field-selector) by the caller
+		listOptions.FieldSelector = fieldSelector
+		// resourceVersion=0 lets the API server answer from its watch cache instead of etcd,
+		// the result may lag behind the latest writes by a little
+		if fromCache {
+			listOptions.ResourceVersion = "0"
+		}
 
-		list, err := resourceInterface.List(ctx, listOptions)
+		list, err := client.list(ctx, gvr, namespace, listOptions)
 		if err != nil {
-			return nil, fmt.Errorf("error listing resources: %v", err)
+			if apierrors.IsForbidden(err) {
+				// Listing across all namespaces is forbidden, fall back to the accessible namespaces
+				if namespaced && namespace == "" {
+					return listPerNamespace(client, gvr, listOptions)
+				}
+				return nil, nil, forbiddenError("list", gvr, namespace)
+			}
+			return nil, nil, fmt.Errorf("error listing resources: %w", err)
 		}
 
-		items = list.Items
+		items = list
 	}
 
-	return items, nil
+	return items, nil, nil
+}
+
+// getResourcesInNamespaces gets resources like getResources, from each of the given namespaces (-n a,b)
+// The namespaces are queried concurrently, the items follow the order of the namespaces and
+// the errors of every namespace are reported together. No namespace means all namespaces.
+func getResourcesInNamespaces(
+	client resourceClient,
+	gvr schema.GroupVersionResource,
+	namespaced bool,
+	namespaces []string,
+	resourceNames []string,
+	labelSelector labels.Selector,
+	fieldSelector string,
+	fromCache bool,
+	concurrency int,
+) ([]unstructured.Unstructured, []string, error) {
+	if !namespaced || len(namespaces) <= 1 {
+		namespace := ""
+		if len(namespaces) == 1 {
+			namespace = namespaces[0]
+		}
+		return getResources(client, gvr, namespaced, namespace, resourceNames, labelSelector, fieldSelector, fromCache)
+	}
+
+	results := make([][]unstructured.Unstructured, len(namespaces))
+	errs := make([]error, len(namespaces))
+	// At most concurrency namespaces are asked at once, 0 means no limit
+	if concurrency <= 0 {
+		concurrency = len(namespaces)
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, namespace := range namespaces {
+		wg.Add(1)
+		go func(i int, namespace string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i], _, errs[i] = getResources(client, gvr, namespaced, namespace, resourceNames, labelSelector, fieldSelector, fromCache)
+		}(i, namespace)
+	}
+	wg.Wait()
+
+	var items []unstructured.Unstructured
+	var failed []error
+	for i, namespace := range namespaces {
+		if errs[i] == nil {
+			items = append(items, results[i]...)
+			continue
+		}
+		// Forbidden errors already name the namespace, the others (e.g. a missing name) get it in front
+		namespaceErrs := []error{errs[i]}
+		var aggregate utilerrors.Aggregate
+		if errors.As(errs[i], &aggregate) {
+			namespaceErrs = aggregate.Errors()
+		}
+		for _, err := range namespaceErrs {
+			if !errors.Is(err, errForbidden) {
+				err = fmt.Errorf("namespace %s: %w", namespace, err)
+			}
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return nil, nil, utilerrors.NewAggregate(failed)
+	}
+
+	return items, nil, nil
+}
+
+// hasGlobPattern checks if any resource name contains shell glob characters (--glob)
+// Kubernetes names never contain *, ? or [, so such a name can only be meant as a pattern
+func hasGlobPattern(resourceNames []string) bool {
+	for _, name := range resourceNames {
+		if strings.ContainsAny(name, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGlobPatterns checks that every pattern can be used with path.Match
+func validateGlobPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern '%s': %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchesNamePatterns checks a name against glob patterns, a pattern without glob characters matches only itself
+func matchesNamePatterns(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterByNamePatterns keeps the items whose name matches one of the patterns (--glob)
+// Items are ordered by the first pattern they match, so the output follows the command line order
+func filterByNamePatterns(items []unstructured.Unstructured, patterns []string) []unstructured.Unstructured {
+	firstMatch := func(name string) int {
+		for i, pattern := range patterns {
+			if matched, _ := path.Match(pattern, name); matched {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var filtered []unstructured.Unstructured
+	for _, item := range items {
+		if firstMatch(item.GetName()) >= 0 {
+			filtered = append(filtered, item)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return firstMatch(filtered[i].GetName()) < firstMatch(filtered[j].GetName())
+	})
+	return filtered
+}
+
+// listPerNamespace lists resources namespace by namespace, skipping namespaces where listing is forbidden
+// Returns the items of the accessible namespaces and the names of the denied namespaces
+func listPerNamespace(
+	client resourceClient,
+	gvr schema.GroupVersionResource,
+	listOptions metav1.ListOptions,
+) ([]unstructured.Unstructured, []string, error) {
+	ctx := context.Background()
+
+	namespaces, err := client.list(ctx, namespaceGVR, "", metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			// Neither the resources nor the namespaces can be listed cluster-wide
+			return nil, nil, forbiddenError("list", gvr, "")
+		}
+		return nil, nil, fmt.Errorf("error listing namespaces: %w", err)
+	}
+
+	var items []unstructured.Unstructured
+	var denied []string
+	var errs []error
+	for _, ns := range namespaces {
+		list, err := client.list(ctx, gvr, ns.GetName(), listOptions)
+		if err != nil {
+			if apierrors.IsForbidden(err) {
+				denied = append(denied, ns.GetName())
+				continue
+			}
+			errs = append(errs, fmt.Errorf("error listing resources in namespace %s: %w", ns.GetName(), err))
+			continue
+		}
+		items = append(items, list...)
+	}
+	if len(errs) > 0 {
+		return nil, nil, utilerrors.NewAggregate(errs)
+	}
+
+	// Nothing was accessible: report it like a regular forbidden error
+	if len(denied) == len(namespaces) {
+		return nil, nil, forbiddenError("list", gvr, "")
+	}
+
+	return items, denied, nil
+}
+
+// errForbidden starts the message of the errors returned by forbiddenError
+var errForbidden = errors.New("forbidden")
+
+// forbiddenError returns a concise, actionable error for a 403 Forbidden response
+// naming the verb, resource and namespace, and the RBAC permission that is missing
+func forbiddenError(verb string, gvr schema.GroupVersionResource, namespace string) error {
+	// RBAC refers to resources as <resource>.<group> (e.g. deployments.apps)
+	resource := gvr.Resource
+	if gvr.Group != "" {
+		resource += "." + gvr.Group
+	}
+
+	if namespace == "" {
+		return fmt.Errorf("%w: cannot %s %q cluster-wide. "+
+			"A ClusterRole granting %q on %q is required (check with: kubectl auth can-i %s %s --all-namespaces)",
+			errForbidden, verb, resource, verb, resource, verb, resource)
+	}
+
+	return fmt.Errorf("%w: cannot %s %q in namespace %q. "+
+		"A Role or ClusterRole bound in namespace %q granting %q on %q is required (check with: kubectl auth can-i %s %s -n %s)",
+		errForbidden, verb, resource, namespace, namespace, verb, resource, verb, resource, namespace)
+}
+
+// namespaceGVR is the GroupVersionResource of Namespaces
+var namespaceGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// getNamespaceLabels returns the labels of a namespace
+func getNamespaceLabels(client dynamic.Interface, namespace string) (map[string]string, error) {
+	ns, err := client.Resource(namespaceGVR).Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, forbiddenError("get", namespaceGVR, "")
+		}
+		return nil, fmt.Errorf("error getting namespace %s: %v", namespace, err)
+	}
+
+	return ns.GetLabels(), nil
+}
+
+// namespaceListPageSize is the number of namespaces asked for per request by listNamespaceNames
+const namespaceListPageSize = 250
+
+// listNamespaceNames returns the names of at most limit namespaces, for shell completion
+// The namespaces are listed in pages so a cluster with thousands of them isn't transferred in full,
+// and the listing stops at the first page that reaches the limit. ctx bounds the whole listing.
+func listNamespaceNames(ctx context.Context, client dynamic.Interface, limit int64) ([]string, error) {
+	var names []string
+	listOptions := metav1.ListOptions{Limit: namespaceListPageSize}
+	for {
+		if remaining := limit - int64(len(names)); remaining < listOptions.Limit {
+			listOptions.Limit = remaining
+		}
+		list, err := client.Resource(namespaceGVR).List(ctx, listOptions)
+		if err != nil {
+			if apierrors.IsForbidden(err) {
+				return nil, forbiddenError("list", namespaceGVR, "")
+			}
+			return nil, fmt.Errorf("error listing namespaces: %w", err)
+		}
+		for _, ns := range list.Items {
+			if int64(len(names)) == limit {
+				return names, nil
+			}
+			names = append(names, ns.GetName())
+		}
+		if list.GetContinue() == "" || int64(len(names)) == limit {
+			return names, nil
+		}
+		listOptions.Continue = list.GetContinue()
+	}
+}
+
+// pdbGVR is the GroupVersionResource of PodDisruptionBudgets
+var pdbGVR = schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}
+
+// getPodDisruptionBudgets lists all PodDisruptionBudgets in the given namespace
+func getPodDisruptionBudgets(client dynamic.Interface, namespace string) ([]unstructured.Unstructured, error) {
+	list, err := client.Resource(pdbGVR).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, forbiddenError("list", pdbGVR, namespace)
+		}
+		return nil, fmt.Errorf("error listing PodDisruptionBudgets in namespace %s: %v", namespace, err)
+	}
+
+	return list.Items, nil
+}
+
+// replicaSetGVR and deploymentGVR are used to resolve the rollout revision of pods
+var (
+	replicaSetGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+)
+
+// rolloutResolver finds the Deployment revision of ReplicaSets, caching ReplicaSets and Deployments
+// since many pods share them
+type rolloutResolver struct {
+	client      dynamic.Interface
+	replicaSets map[string]*RolloutRevisionInfo
+	deployments map[string]string
+}
+
+// newRolloutResolver creates a rolloutResolver with empty caches
+func newRolloutResolver(client dynamic.Interface) *rolloutResolver {
+	return &rolloutResolver{
+		client:      client,
+		replicaSets: make(map[string]*RolloutRevisionInfo),
+		deployments: make(map[string]string),
+	}
+}
+
+// resolve compares the revision of a ReplicaSet with the latest revision of its Deployment
+// Returns nil when the ReplicaSet is gone or isn't owned by a Deployment
+func (r *rolloutResolver) resolve(namespace, replicaSetName string) (*RolloutRevisionInfo, error) {
+	key := namespace + "/" + replicaSetName
+	if info, ok := r.replicaSets[key]; ok {
+		return info, nil
+	}
+
+	ctx := context.Background()
+	replicaSet, err := r.client.Resource(replicaSetGVR).Namespace(namespace).Get(ctx, replicaSetName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.replicaSets[key] = nil
+			return nil, nil
+		}
+		if apierrors.IsForbidden(err) {
+			return nil, forbiddenError("get", replicaSetGVR, namespace)
+		}
+		return nil, fmt.Errorf("error getting ReplicaSet %s: %v", replicaSetName, err)
+	}
+
+	var info *RolloutRevisionInfo
+	for _, ownerRef := range replicaSet.GetOwnerReferences() {
+		if ownerRef.Kind != "Deployment" {
+			continue
+		}
+
+		deploymentKey := namespace + "/" + ownerRef.Name
+		latestRevision, ok := r.deployments[deploymentKey]
+		if !ok {
+			deployment, err := r.client.Resource(deploymentGVR).Namespace(namespace).Get(ctx, ownerRef.Name, metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				if apierrors.IsForbidden(err) {
+					return nil, forbiddenError("get", deploymentGVR, namespace)
+				}
+				return nil, fmt.Errorf("error getting Deployment %s: %v", ownerRef.Name, err)
+			}
+			if err == nil {
+				latestRevision = deployment.GetAnnotations()[revisionAnnotation]
+			}
+			r.deployments[deploymentKey] = latestRevision
+		}
+
+		revision := replicaSet.GetAnnotations()[revisionAnnotation]
+		info = &RolloutRevisionInfo{
+			Deployment:     ownerRef.Name,
+			ReplicaSet:     replicaSetName,
+			Revision:       revision,
+			LatestRevision: latestRevision,
+			Current:        revision != "" && revision == latestRevision,
+		}
+		break
+	}
+
+	r.replicaSets[key] = info
+	return info, nil
+}
+
+// priorityClassGVR is the GroupVersionResource of PriorityClasses
+var priorityClassGVR = schema.GroupVersionResource{Group: "scheduling.k8s.io", Version: "v1", Resource: "priorityclasses"}
+
+// priorityClassResolver looks up the PriorityClass a pod's priority comes from (--resolve-priority)
+// Classes are fetched once, most pods of a cluster share a handful of them
+type priorityClassResolver struct {
+	client  dynamic.Interface
+	classes map[string]*PriorityClassInfo
+	// globalDefault is the class used by pods that don't name one, loaded on first use
+	globalDefault       *PriorityClassInfo
+	globalDefaultLoaded bool
+}
+
+// newPriorityClassResolver creates a priorityClassResolver with an empty cache
+func newPriorityClassResolver(client dynamic.Interface) *priorityClassResolver {
+	return &priorityClassResolver{client: client, classes: make(map[string]*PriorityClassInfo)}
+}
+
+// resolve returns the named PriorityClass, marked missing when it doesn't exist
+// Without a name it returns the globalDefault class, or nil when the cluster has none (the priority is then 0)
+func (r *priorityClassResolver) resolve(name string) (*PriorityClassInfo, error) {
+	if name == "" {
+		return r.defaultClass()
+	}
+	if info, ok := r.classes[name]; ok {
+		return info, nil
+	}
+
+	class, err := r.client.Resource(priorityClassGVR).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		if apierrors.IsForbidden(err) {
+			return nil, forbiddenError("get", priorityClassGVR, "")
+		}
+		return nil, fmt.Errorf("error getting PriorityClass %s: %v", name, err)
+	}
+
+	info := &PriorityClassInfo{Name: name, Missing: true}
+	if err == nil {
+		info = priorityClassInfo(*class)
+	}
+	r.classes[name] = info
+	return info, nil
+}
+
+// defaultClass lists the PriorityClasses once to find the globalDefault one, caching the others on the way
+func (r *priorityClassResolver) defaultClass() (*PriorityClassInfo, error) {
+	if r.globalDefaultLoaded {
+		return r.globalDefault, nil
+	}
+
+	list, err := r.client.Resource(priorityClassGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, forbiddenError("list", priorityClassGVR, "")
+		}
+		return nil, fmt.Errorf("error listing PriorityClasses: %v", err)
+	}
+	for _, class := range list.Items {
+		info := priorityClassInfo(class)
+		r.classes[info.Name] = info
+		if info.GlobalDefault {
+			r.globalDefault = info
+		}
+	}
+	r.globalDefaultLoaded = true
+	return r.globalDefault, nil
+}
+
+// priorityClassInfo extracts the value, globalDefault and preemptionPolicy of a PriorityClass
+func priorityClassInfo(class unstructured.Unstructured) *PriorityClassInfo {
+	info := &PriorityClassInfo{Name: class.GetName()}
+	if value, found, _ := unstructured.NestedInt64(class.Object, "value"); found {
+		info.Value = &value
+	}
+	info.GlobalDefault, _, _ = unstructured.NestedBool(class.Object, "globalDefault")
+	info.PreemptionPolicy, _, _ = unstructured.NestedString(class.Object, "preemptionPolicy")
+	return info
+}
+
+// configMapGVR and secretGVR are read by env --expand-refs
+var (
+	configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretGVR    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+// envSourceResolver lists the keys of the ConfigMaps and Secrets referenced by envFrom,
+// caching them since the pods of a workload share their references
+type envSourceResolver struct {
+	client dynamic.Interface
+	keys   map[string][]string
+}
+
+// newEnvSourceResolver creates an envSourceResolver with an empty cache
+func newEnvSourceResolver(client dynamic.Interface) *envSourceResolver {
+	return &envSourceResolver{client: client, keys: make(map[string][]string)}
+}
+
+// expand fills the variables of every envFrom source of the containers, prefix included
+// Sources that don't exist are marked missing instead of failing
+func (r *envSourceResolver) expand(namespace string, containers []ContainerEnv) error {
+	for i := range containers {
+		for j := range containers[i].EnvFrom {
+			source := &containers[i].EnvFrom[j]
+			keys, found, err := r.sourceKeys(source.Kind, namespace, source.Name)
+			if err != nil {
+				return err
+			}
+			source.Missing = !found
+			for _, key := range keys {
+				source.Variables = append(source.Variables, source.Prefix+key)
+			}
+		}
+	}
+	return nil
+}
+
+// sourceKeys returns the sorted keys of a ConfigMap or Secret, found is false when it doesn't exist
+// Only the key names are kept, secret values never leave this function
+func (r *envSourceResolver) sourceKeys(kind, namespace, name string) ([]string, bool, error) {
+	cacheKey := kind + "/" + namespace + "/" + name
+	if keys, ok := r.keys[cacheKey]; ok {
+		return keys, keys != nil, nil
+	}
+
+	gvr := configMapGVR
+	if kind == "Secret" {
+		gvr = secretGVR
+	}
+	object, err := r.client.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.keys[cacheKey] = nil
+			return nil, false, nil
+		}
+		if apierrors.IsForbidden(err) {
+			return nil, false, forbiddenError("get", gvr, namespace)
+		}
+		return nil, false, fmt.Errorf("error getting %s %s: %v", kind, name, err)
+	}
+
+	// Non-nil even when empty, so the cache tells empty and missing apart
+	keys := []string{}
+	for _, field := range []string{"data", "binaryData"} {
+		values, _, _ := unstructured.NestedMap(object.Object, field)
+		for key := range values {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	r.keys[cacheKey] = keys
+	return keys, true, nil
+}
+
+// podMetricsGVR is the GroupVersionResource of PodMetrics served by metrics-server
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// getPodMetrics returns the current usage of each container, keyed by namespace/pod and container name
+// An empty namespace lists the metrics of all namespaces
+func getPodMetrics(client dynamic.Interface, namespace string) (map[string]map[string]map[string]interface{}, error) {
+	list, err := client.Resource(podMetricsGVR).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("metrics API (metrics.k8s.io) is not available, is metrics-server installed? (%v)", err)
+	}
+
+	usageByPod := make(map[string]map[string]map[string]interface{})
+	for _, podMetrics := range list.Items {
+		containers, found, _ := unstructured.NestedSlice(podMetrics.Object, "containers")
+		if !found {
+			continue
+		}
+
+		usageByContainer := make(map[string]map[string]interface{})
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			containerName, _ := containerMap["name"].(string)
+			if usage, ok := containerMap["usage"].(map[string]interface{}); ok {
+				usageByContainer[containerName] = usage
+			}
+		}
+		usageByPod[podMetrics.GetNamespace()+"/"+podMetrics.GetName()] = usageByContainer
+	}
+
+	return usageByPod, nil
 }
@@ -0,0 +1,845 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// newTestPod returns a minimal unstructured Pod
+func newTestPod(namespace, name string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace(namespace)
+	pod.SetName(name)
+	return pod
+}
+
+func itemNames(items []unstructured.Unstructured) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.GetName())
+	}
+	return names
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	testPodGVR  = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	testNodeGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+)
+
+// newFakeDynamicClient returns a fake dynamic client serving the given objects
+// List kinds are registered for every resource the plugin queries, so tests can list any of them
+func newFakeDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	listKinds := map[schema.GroupVersionResource]string{
+		testPodGVR:       "PodList",
+		testNodeGVR:      "NodeList",
+		namespaceGVR:     "NamespaceList",
+		pdbGVR:           "PodDisruptionBudgetList",
+		replicaSetGVR:    "ReplicaSetList",
+		deploymentGVR:    "DeploymentList",
+		configMapGVR:     "ConfigMapList",
+		secretGVR:        "SecretList",
+		priorityClassGVR: "PriorityClassList",
+		{Group: "batch", Version: "v1", Resource: "cronjobs"}: "CronJobList",
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
+
+// newTestNode returns a minimal unstructured Node
+func newTestNode(name string) *unstructured.Unstructured {
+	node := &unstructured.Unstructured{}
+	node.SetAPIVersion("v1")
+	node.SetKind("Node")
+	node.SetName(name)
+	return node
+}
+
+// newTestNamespace returns a minimal unstructured Namespace
+func newTestNamespace(name string) *unstructured.Unstructured {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName(name)
+	return namespace
+}
+
+func TestGetResources(t *testing.T) {
+	web := newTestPod("default", "web")
+	web.SetLabels(map[string]string{"app": "web"})
+	api := newTestPod("default", "api")
+	api.SetLabels(map[string]string{"app": "api"})
+	dns := newTestPod("kube-system", "coredns")
+	client := dynamicResourceClient{client: newFakeDynamicClient(web, api, dns, newTestNode("node-1"), newTestNode("node-2"))}
+
+	tests := []struct {
+		name       string
+		gvr        schema.GroupVersionResource
+		namespaced bool
+		namespace  string
+		names      []string
+		selector   string
+		want       []string
+	}{
+		{name: "list in namespace", gvr: testPodGVR, namespaced: true, namespace: "default", want: []string{"api", "web"}},
+		{name: "list all namespaces", gvr: testPodGVR, namespaced: true, want: []string{"api", "coredns", "web"}},
+		{name: "named", gvr: testPodGVR, namespaced: true, namespace: "kube-system", names: []string{"coredns"}, want: []string{"coredns"}},
+		{name: "selector", gvr: testPodGVR, namespaced: true, namespace: "default", selector: "app=web", want: []string{"web"}},
+		{name: "cluster-scoped ignores namespace", gvr: testNodeGVR, namespace: "default", want: []string{"node-1", "node-2"}},
+		{name: "cluster-scoped named", gvr: testNodeGVR, namespace: "default", names: []string{"node-2"}, want: []string{"node-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selector labels.Selector
+			if tt.selector != "" {
+				var err error
+				if selector, err = labels.Parse(tt.selector); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			items, _, err := getResources(client, tt.gvr, tt.namespaced, tt.namespace, tt.names, selector, "", false)
+			if err != nil {
+				t.Fatalf("getResources() error = %v", err)
+			}
+			// The fake client lists in no particular order
+			got := itemNames(items)
+			sort.Strings(got)
+			if !equalStrings(got, tt.want) {
+				t.Errorf("getResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetResourcesNotFound(t *testing.T) {
+	client := dynamicResourceClient{client: newFakeDynamicClient()}
+	if _, _, err := getResources(client, testPodGVR, true, "default", []string{"missing"}, nil, "", false); err == nil {
+		t.Error("getResources() error = nil, want an error for a missing resource")
+	}
+}
+
+func TestGetResourcesReportsEveryMissingName(t *testing.T) {
+	client := dynamicResourceClient{client: newFakeDynamicClient(newTestPod("default", "web"))}
+	_, _, err := getResources(client, testPodGVR, true, "default", []string{"api", "web", "db"}, nil, "", false)
+
+	var aggregate utilerrors.Aggregate
+	if !errors.As(err, &aggregate) {
+		t.Fatalf("getResources() error = %v, want an aggregate", err)
+	}
+	if got := len(aggregate.Errors()); got != 2 {
+		t.Fatalf("getResources() errors = %v, want one per missing name", aggregate.Errors())
+	}
+	for i, name := range []string{"api", "db"} {
+		if e := aggregate.Errors()[i]; !apierrors.IsNotFound(e) || !strings.Contains(e.Error(), "error getting "+name) {
+			t.Errorf("error %d = %v, want not found for %s", i, e, name)
+		}
+	}
+}
+
+func TestGetResourcesInNamespaces(t *testing.T) {
+	client := newFakeDynamicClient(
+		newTestPod("staging", "web"),
+		newTestPod("prod", "web"),
+		newTestPod("prod", "api"),
+		newTestPod("dev", "web"),
+	)
+
+	items, _, err := getResourcesInNamespaces(dynamicResourceClient{client: client}, testPodGVR, true, []string{"staging", "prod"}, nil, nil, "", false, 0)
+	if err != nil {
+		t.Fatalf("getResourcesInNamespaces() error = %v", err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.GetNamespace()+"/"+item.GetName())
+	}
+	// Namespaces keep their order, the order within a namespace is the API server's
+	sort.Strings(got[1:])
+	if want := []string{"staging/web", "prod/api", "prod/web"}; !equalStrings(got, want) {
+		t.Errorf("getResourcesInNamespaces() = %v, want %v", got, want)
+	}
+
+	// A name missing from one of the namespaces is reported with that namespace
+	_, _, err = getResourcesInNamespaces(dynamicResourceClient{client: client}, testPodGVR, true, []string{"staging", "prod"}, []string{"api"}, nil, "", false, 1)
+	if err == nil || !strings.Contains(err.Error(), "namespace staging: error getting api") {
+		t.Errorf("getResourcesInNamespaces() error = %v, want the missing name in staging", err)
+	}
+	if strings.Contains(err.Error(), "namespace prod") {
+		t.Errorf("getResourcesInNamespaces() error = %v, want only staging", err)
+	}
+}
+
+func TestFilterByNamePatterns(t *testing.T) {
+	items := []unstructured.Unstructured{
+		*newTestPod("default", "api-1"),
+		*newTestPod("default", "web-1"),
+		*newTestPod("default", "web-2"),
+		*newTestPod("default", "db-0"),
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{name: "star", patterns: []string{"web-*"}, want: []string{"web-1", "web-2"}},
+		{name: "command line order", patterns: []string{"db-?", "web-*"}, want: []string{"db-0", "web-1", "web-2"}},
+		{name: "literal name", patterns: []string{"web-*", "api-1"}, want: []string{"web-1", "web-2", "api-1"}},
+		{name: "character class", patterns: []string{"[aw]*-1"}, want: []string{"api-1", "web-1"}},
+		{name: "no match", patterns: []string{"cache-*"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemNames(filterByNamePatterns(items, tt.patterns)); !equalStrings(got, tt.want) {
+				t.Errorf("filterByNamePatterns(%v) = %v, want %v", tt.patterns, got, tt.want)
+			}
+		})
+	}
+
+	if !hasGlobPattern([]string{"api", "web-*"}) || hasGlobPattern([]string{"api", "web-1"}) {
+		t.Errorf("hasGlobPattern() should only report names with *, ? or [")
+	}
+	if err := validateGlobPatterns([]string{"web-*", "web-["}); err == nil || !strings.Contains(err.Error(), "web-[") {
+		t.Errorf("validateGlobPatterns() error = %v, want the invalid pattern", err)
+	}
+}
+
+func TestGetResourcesForbiddenFallsBackPerNamespace(t *testing.T) {
+	client := newFakeDynamicClient(
+		newTestPod("team-a", "web"),
+		newTestPod("team-b", "api"),
+		newTestNamespace("team-a"),
+		newTestNamespace("team-b"),
+	)
+	// Deny the cluster-wide list and team-b, like a user bound to a single namespace
+	client.PrependReactor("list", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if ns := action.GetNamespace(); ns == "" || ns == "team-b" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied"))
+		}
+		return false, nil, nil
+	})
+
+	items, denied, err := getResources(dynamicResourceClient{client: client}, testPodGVR, true, "", nil, nil, "", false)
+	if err != nil {
+		t.Fatalf("getResources() error = %v", err)
+	}
+	if got := itemNames(items); !equalStrings(got, []string{"web"}) {
+		t.Errorf("getResources() = %v, want [web]", got)
+	}
+	if !equalStrings(denied, []string{"team-b"}) {
+		t.Errorf("getResources() denied = %v, want [team-b]", denied)
+	}
+}
+
+func TestGetResourcesTriesEveryName(t *testing.T) {
+	client := newFakeDynamicClient(newTestPod("default", "web"), newTestPod("default", "secret-1"), newTestPod("default", "secret-2"))
+	// Deny two of the names, like a Role with resourceNames
+	client.PrependReactor("get", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if name := action.(clienttesting.GetAction).GetName(); strings.HasPrefix(name, "secret-") {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, name, errors.New("denied"))
+		}
+		return false, nil, nil
+	})
+
+	names := []string{"secret-1", "missing", "secret-2", "web"}
+	_, _, err := getResources(dynamicResourceClient{client: client}, testPodGVR, true, "default", names, nil, "", false)
+	var aggregate utilerrors.Aggregate
+	if !errors.As(err, &aggregate) || len(aggregate.Errors()) != 2 {
+		t.Fatalf("getResources() error = %v, want the missing and the forbidden names", err)
+	}
+	if !strings.Contains(err.Error(), "error getting missing") {
+		t.Errorf("getResources() error = %v, want the missing name", err)
+	}
+	if !errors.Is(err, errForbidden) || !strings.Contains(err.Error(), "error getting secret-1, secret-2") {
+		t.Errorf("getResources() error = %v, want both forbidden names in one error", err)
+	}
+}
+
+func TestListNamespaceNames(t *testing.T) {
+	// Serve three namespaces in pages of two, like an API server with a smaller page size than asked for
+	pages := [][]string{{"team-a", "team-b"}, {"team-c"}}
+	client := newFakeDynamicClient()
+	calls := 0
+	client.PrependReactor("list", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion("v1")
+		list.SetKind("NamespaceList")
+		for _, name := range pages[calls] {
+			list.Items = append(list.Items, *newTestNamespace(name))
+		}
+		calls++
+		if calls < len(pages) {
+			list.SetContinue(fmt.Sprintf("page-%d", calls))
+		}
+		return true, list, nil
+	})
+
+	names, err := listNamespaceNames(context.Background(), client, 10)
+	if err != nil {
+		t.Fatalf("listNamespaceNames() error = %v", err)
+	}
+	if !equalStrings(names, []string{"team-a", "team-b", "team-c"}) {
+		t.Errorf("listNamespaceNames() = %v, want every page", names)
+	}
+
+	// The limit stops the listing before the next page is asked for
+	calls = 0
+	names, err = listNamespaceNames(context.Background(), client, 2)
+	if err != nil {
+		t.Fatalf("listNamespaceNames() error = %v", err)
+	}
+	if !equalStrings(names, []string{"team-a", "team-b"}) || calls != 1 {
+		t.Errorf("listNamespaceNames() = %v after %d requests, want [team-a team-b] after 1", names, calls)
+	}
+
+	// A page larger than the limit is cut
+	calls = 0
+	names, err = listNamespaceNames(context.Background(), client, 1)
+	if err != nil {
+		t.Fatalf("listNamespaceNames() error = %v", err)
+	}
+	if !equalStrings(names, []string{"team-a"}) {
+		t.Errorf("listNamespaceNames() = %v, want [team-a]", names)
+	}
+}
+
+// listOptionsRecorder is a resourceClient that remembers the options of the last list call
+type listOptionsRecorder struct {
+	opts metav1.ListOptions
+}
+
+func (r *listOptionsRecorder) get(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	return newTestPod(namespace, name), nil
+}
+
+func (r *listOptionsRecorder) list(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	r.opts = opts
+	return nil, nil
+}
+
+func TestGetResourcesFromCache(t *testing.T) {
+	for _, fromCache := range []bool{false, true} {
+		recorder := &listOptionsRecorder{}
+		if _, _, err := getResources(recorder, testPodGVR, true, "default", nil, nil, "", fromCache); err != nil {
+			t.Fatalf("getResources() error = %v", err)
+		}
+		want := ""
+		if fromCache {
+			want = "0"
+		}
+		if recorder.opts.ResourceVersion != want {
+			t.Errorf("fromCache=%v: ResourceVersion = %q, want %q", fromCache, recorder.opts.ResourceVersion, want)
+		}
+	}
+}
+
+func TestGetResourcesPreservesRequestedNameOrder(t *testing.T) {
+	client := newFakeDynamicClient(
+		newTestPod("default", "alpha"),
+		newTestPod("default", "bravo"),
+		newTestPod("default", "charlie"),
+	)
+
+	requested := []string{"charlie", "alpha", "bravo"}
+	items, denied, err := getResources(dynamicResourceClient{client: client}, testPodGVR, true, "default", requested, nil, "", false)
+	if err != nil {
+		t.Fatalf("getResources() error = %v", err)
+	}
+	if len(denied) != 0 {
+		t.Errorf("getResources() denied namespaces = %v, want none", denied)
+	}
+	if got := itemNames(items); !equalStrings(got, requested) {
+		t.Errorf("getResources() order = %v, want %v", got, requested)
+	}
+}
+
+func TestGetResourcesWithMetadataClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pod := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web",
+			Namespace:       "default",
+			Labels:          map[string]string{"app": "web"},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-7d9f8"}},
+		},
+	}
+	client := metadatafake.NewSimpleMetadataClient(scheme, pod)
+
+	items, _, err := getResources(metadataResourceClient{client: client}, testPodGVR, true, "default", nil, nil, "", false)
+	if err != nil {
+		t.Fatalf("getResources() error = %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("getResources() returned %d items, want 1", len(items))
+	}
+	if got := items[0].GetLabels()["app"]; got != "web" {
+		t.Errorf("label app = %q, want %q", got, "web")
+	}
+	if owners := extractOwnerReferences(items[0]); len(owners) != 1 || owners[0].Kind != "ReplicaSet" {
+		t.Errorf("extractOwnerReferences() = %v, want one ReplicaSet owner", owners)
+	}
+}
+
+func TestTrimResourceNames(t *testing.T) {
+	client := dynamicResourceClient{client: newFakeDynamicClient(newTestPod("default", "web"), newTestPod("default", "api"))}
+
+	names, err := trimResourceNames([]string{" web", "api\t\n"})
+	if err != nil {
+		t.Fatalf("trimResourceNames() error = %v", err)
+	}
+	items, _, err := getResources(client, testPodGVR, true, "default", names, nil, "", false)
+	if err != nil {
+		t.Fatalf("getResources() with trimmed names error = %v", err)
+	}
+	if got := itemNames(items); !equalStrings(got, []string{"web", "api"}) {
+		t.Errorf("getResources() = %v, want [web api]", got)
+	}
+
+	if _, err := trimResourceNames([]string{"web", "  "}); err == nil {
+		t.Error("trimResourceNames() error = nil, want an error for a blank name")
+	}
+}
+
+func TestFilterObjectsPreservesRequestedNameOrder(t *testing.T) {
+	objects := []unstructured.Unstructured{
+		*newTestPod("default", "alpha"),
+		*newTestPod("default", "bravo"),
+		*newTestPod("default", "charlie"),
+	}
+
+	requested := []string{"charlie", "alpha"}
+	got := itemNames(filterObjects(objects, nil, requested, nil))
+	if !equalStrings(got, requested) {
+		t.Errorf("filterObjects() order = %v, want %v", got, requested)
+	}
+}
+
+// newBenchmarkPod returns a Pod with a spec and status of realistic size
+func newBenchmarkPod(i int) *unstructured.Unstructured {
+	pod := newTestPod("default", fmt.Sprintf("web-%d", i))
+	pod.SetLabels(map[string]string{"app": "web", "pod-template-hash": "7d9f8c6b5d"})
+	pod.SetAnnotations(map[string]string{"kubectl.kubernetes.io/restartedAt": "2024-01-01T00:00:00Z"})
+
+	container := map[string]interface{}{
+		"name":  "web",
+		"image": "registry.example.com/team/web:1.2.3",
+		"args":  []interface{}{"--port=8080", "--log-level=info"},
+		"env": []interface{}{
+			map[string]interface{}{"name": "DATABASE_URL", "value": "postgres://db.default.svc:5432/web"},
+			map[string]interface{}{"name": "CACHE_URL", "value": "redis://cache.default.svc:6379"},
+		},
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "250m", "memory": "256Mi"},
+			"limits":   map[string]interface{}{"memory": "512Mi"},
+		},
+		"readinessProbe": map[string]interface{}{
+			"httpGet":       map[string]interface{}{"path": "/healthz", "port": int64(8080)},
+			"periodSeconds": int64(10),
+		},
+	}
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{container, container}, "spec", "containers")
+	_ = unstructured.SetNestedSlice(pod.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True", "lastTransitionTime": "2024-01-01T00:00:00Z"},
+		map[string]interface{}{"type": "PodScheduled", "status": "True", "lastTransitionTime": "2024-01-01T00:00:00Z"},
+	}, "status", "conditions")
+	return pod
+}
+
+// BenchmarkListPayload compares the size of a full List response with the metadata-only
+// (PartialObjectMetadataList) response used for labels, annotations, owner and revision
+func BenchmarkListPayload(b *testing.B) {
+	const podCount = 500
+
+	full := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "PodList"}}
+	partial := &metav1.PartialObjectMetadataList{}
+	for i := 0; i < podCount; i++ {
+		pod := newBenchmarkPod(i)
+		full.Items = append(full.Items, *pod)
+		partial.Items = append(partial.Items, metav1.PartialObjectMetadata{
+			TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        pod.GetName(),
+				Namespace:   pod.GetNamespace(),
+				Labels:      pod.GetLabels(),
+				Annotations: pod.GetAnnotations(),
+			},
+		})
+	}
+
+	b.Run("full", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			data, err := full.MarshalJSON()
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(data)
+		}
+		b.ReportMetric(float64(size), "bytes/list")
+	})
+
+	b.Run("metadata", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			data, err := json.Marshal(partial)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(data)
+		}
+		b.ReportMetric(float64(size), "bytes/list")
+	})
+}
+
+func TestDiscoveryFailedGroups(t *testing.T) {
+	err := &discovery.ErrGroupDiscoveryFailed{Groups: map[schema.GroupVersion]error{
+		{Group: "metrics.k8s.io", Version: "v1beta1"}:   errors.New("the server is currently unable to handle the request"),
+		{Group: "custom.metrics.k8s.io", Version: "v1"}: errors.New("timeout"),
+	}}
+
+	want := []string{
+		"custom.metrics.k8s.io/v1 (timeout)",
+		"metrics.k8s.io/v1beta1 (the server is currently unable to handle the request)",
+	}
+	if got := discoveryFailedGroups(err); !equalStrings(got, want) {
+		t.Errorf("discoveryFailedGroups() = %q, want %q", got, want)
+	}
+}
+
+// flakyDiscovery serves resource lists, answering each group version only after it failed a number of times
+type flakyDiscovery struct {
+	discovery.ServerResourcesInterface
+	mu       sync.Mutex
+	lists    map[string]*metav1.APIResourceList
+	failures map[string]int
+}
+
+func (d *flakyDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.failures[groupVersion] > 0 {
+		d.failures[groupVersion]--
+		return nil, errors.New("the server is currently unable to handle the request")
+	}
+	return d.lists[groupVersion], nil
+}
+
+func TestRetryGroupVersions(t *testing.T) {
+	previous := discoveryRetryDelay
+	discoveryRetryDelay = 0
+	t.Cleanup(func() { discoveryRetryDelay = previous })
+
+	metrics := schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	broken := schema.GroupVersion{Group: "broken.example.com", Version: "v1"}
+	client := &flakyDiscovery{
+		lists: map[string]*metav1.APIResourceList{
+			metrics.String(): {GroupVersion: metrics.String(), APIResources: []metav1.APIResource{{Name: "pods", Kind: "PodMetrics", Namespaced: true}}},
+		},
+		failures: map[string]int{metrics.String(): discoveryRetryAttempts - 1, broken.String(): discoveryRetryAttempts},
+	}
+
+	recovered, err := retryGroupVersions(client, map[schema.GroupVersion]error{
+		metrics: errors.New("timeout"),
+		broken:  errors.New("timeout"),
+	})
+	if len(recovered) != 1 || recovered[0].GroupVersion != metrics.String() {
+		t.Errorf("retryGroupVersions() recovered = %v, want %s only", recovered, metrics)
+	}
+	want := []string{"broken.example.com/v1 (the server is currently unable to handle the request)"}
+	if got := discoveryFailedGroups(err); !equalStrings(got, want) {
+		t.Errorf("retryGroupVersions() still failing = %q, want %q", got, want)
+	}
+}
+
+func TestAppendMissingGroups(t *testing.T) {
+	lists := []*metav1.APIResourceList{{GroupVersion: "v1"}, {GroupVersion: "autoscaling/v2"}}
+	recovered := []*metav1.APIResourceList{{GroupVersion: "autoscaling/v1"}, {GroupVersion: "metrics.k8s.io/v1beta1"}}
+
+	var got []string
+	for _, list := range appendMissingGroups(lists, recovered) {
+		got = append(got, list.GroupVersion)
+	}
+	want := []string{"v1", "autoscaling/v2", "metrics.k8s.io/v1beta1"}
+	if !equalStrings(got, want) {
+		t.Errorf("appendMissingGroups() = %q, want %q", got, want)
+	}
+}
+
+func TestListableResources(t *testing.T) {
+	listVerbs := metav1.Verbs{"get", "list", "watch"}
+	apiResourceLists := []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: listVerbs},
+			{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+			{Name: "events", Kind: "Event", Namespaced: true, Verbs: listVerbs},
+			{Name: "nodes", Kind: "Node", Verbs: listVerbs},
+			{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: metav1.Verbs{"create"}},
+		}},
+		{GroupVersion: "events.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "events", Kind: "Event", Namespaced: true, Verbs: listVerbs},
+		}},
+		{GroupVersion: "rbac.authorization.k8s.io/v1", APIResources: []metav1.APIResource{
+			{Name: "clusterroles", Kind: "ClusterRole", Verbs: listVerbs},
+		}},
+		nil,
+	}
+
+	tests := []struct {
+		scope string
+		want  []string
+	}{
+		{scopeAll, []string{"pods", "events", "nodes", "clusterroles.rbac.authorization.k8s.io"}},
+		{scopeNamespaced, []string{"pods", "events"}},
+		{scopeCluster, []string{"nodes", "clusterroles.rbac.authorization.k8s.io"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, resource := range listableResources(apiResourceLists, tt.scope) {
+			got = append(got, resource.GVR.GroupResource().String())
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("listableResources(%s) = %q, want %q", tt.scope, got, tt.want)
+		}
+	}
+
+	// Without the core events, the events of events.k8s.io are the only ones and are kept
+	onlyNewEvents := apiResourceLists[1:]
+	var got []string
+	for _, resource := range listableResources(onlyNewEvents, scopeAll) {
+		got = append(got, resource.GVR.GroupResource().String())
+	}
+	if want := []string{"events.events.k8s.io", "clusterroles.rbac.authorization.k8s.io"}; !equalStrings(got, want) {
+		t.Errorf("listableResources() without core events = %q, want %q", got, want)
+	}
+}
+
+func TestIsSkippableListError(t *testing.T) {
+	deploymentsGR := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	methodNotAllowed := apierrors.NewMethodNotSupported(deploymentsGR, "list")
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"forbidden", forbiddenError("list", testPodGVR, "default"), true},
+		{"method not allowed", fmt.Errorf("error listing resources: %w", methodNotAllowed), true},
+		{"service unavailable", apierrors.NewServiceUnavailable("metrics-server is down"), true},
+		{"every namespace failed on the server", utilerrors.NewAggregate([]error{
+			fmt.Errorf("namespace a: %w", methodNotAllowed),
+			forbiddenError("list", testPodGVR, "b"),
+		}), true},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:6443: connect: connection refused"), false},
+		{"one namespace failed on the client", utilerrors.NewAggregate([]error{
+			fmt.Errorf("namespace a: %w", methodNotAllowed),
+			errors.New("namespace b: connection reset by peer"),
+		}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSkippableListError(tt.err); got != tt.want {
+				t.Errorf("isSkippableListError(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	if got, want := listErrorMessage(fmt.Errorf("error listing resources: %w", methodNotAllowed)), methodNotAllowed.ErrStatus.Message; got != want {
+		t.Errorf("listErrorMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestFindAPIResource(t *testing.T) {
+	apiResourceLists := []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", Namespaced: true, ShortNames: []string{"po"}},
+			{Name: "pods/log", Kind: "Pod", Namespaced: true},
+			{Name: "nodes", Kind: "Node", ShortNames: []string{"no"}},
+			{Name: "endpoints", Kind: "Endpoints", Namespaced: true, ShortNames: []string{"ep"}},
+		}},
+		{GroupVersion: "mesh.example.com/v1", APIResources: []metav1.APIResource{
+			{Name: "proxies", Kind: "ProxyConfig", Namespaced: true},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, ShortNames: []string{"deploy"}},
+		}},
+	}
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	proxiesGVR := schema.GroupVersionResource{Group: "mesh.example.com", Version: "v1", Resource: "proxies"}
+
+	tests := []struct {
+		resourceType string
+		want         resolvedResource
+	}{
+		{"pods", resolvedResource{GVR: testPodGVR, Kind: "Pod", Namespaced: true}},
+		{"PO", resolvedResource{GVR: testPodGVR, Kind: "Pod", Namespaced: true}},
+		{"Node", resolvedResource{GVR: testNodeGVR, Kind: "Node"}},
+		{"deploy", resolvedResource{GVR: deploymentsGVR, Kind: "Deployment", Namespaced: true}},
+		{"deployments.apps", resolvedResource{GVR: deploymentsGVR, Kind: "Deployment", Namespaced: true}},
+		// Singular and plural guesses for names that no kind or short name covers
+		{"endpoint", resolvedResource{GVR: schema.GroupVersionResource{Version: "v1", Resource: "endpoints"}, Kind: "Endpoints", Namespaced: true}},
+		{"Proxy", resolvedResource{GVR: proxiesGVR, Kind: "ProxyConfig", Namespaced: true}},
+		{"proxie", resolvedResource{GVR: proxiesGVR, Kind: "ProxyConfig", Namespaced: true}},
+	}
+	for _, tt := range tests {
+		got, err := findAPIResource(apiResourceLists, nil, tt.resourceType)
+		if err != nil {
+			t.Fatalf("findAPIResource(%q) error = %v", tt.resourceType, err)
+		}
+		if got != tt.want {
+			t.Errorf("findAPIResource(%q) = %+v, want %+v", tt.resourceType, got, tt.want)
+		}
+	}
+
+	// The core group has no full name form
+	if _, err := findAPIResource(apiResourceLists, nil, "pods."); err == nil {
+		t.Error("findAPIResource(pods.) error = nil, want not found")
+	}
+
+	_, err := findAPIResource(apiResourceLists, []string{"metrics.k8s.io/v1beta1 (unavailable)"}, "podmetrics")
+	var notFound *resourceTypeNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("findAPIResource(podmetrics) error = %v, want a resourceTypeNotFoundError", err)
+	}
+	if want := "Discovery failed for metrics.k8s.io/v1beta1 (unavailable)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("findAPIResource(podmetrics) error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestEnvSourceResolverExpand(t *testing.T) {
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "app-config", "namespace": "default"},
+		"data":       map[string]interface{}{"LOG_LEVEL": "info", "CACHE_SIZE": "64"},
+		"binaryData": map[string]interface{}{"CERT": "AAAA"},
+	}}
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "api-keys", "namespace": "default"},
+		"data":       map[string]interface{}{"TOKEN": "c2VjcmV0"},
+	}}
+	client := newFakeDynamicClient(configMap, secret)
+	resolver := newEnvSourceResolver(client)
+
+	containers := []ContainerEnv{{
+		Name: "app",
+		EnvFrom: []EnvFromSource{
+			{Kind: "ConfigMap", Name: "app-config", Prefix: "APP_"},
+			{Kind: "Secret", Name: "api-keys"},
+			{Kind: "Secret", Name: "missing", Optional: true},
+		},
+	}}
+	if err := resolver.expand("default", containers); err != nil {
+		t.Fatalf("expand() error = %v", err)
+	}
+
+	envFrom := containers[0].EnvFrom
+	if want := []string{"APP_CACHE_SIZE", "APP_CERT", "APP_LOG_LEVEL"}; !equalStrings(envFrom[0].Variables, want) {
+		t.Errorf("ConfigMap variables = %v, want %v", envFrom[0].Variables, want)
+	}
+	if want := []string{"TOKEN"}; !equalStrings(envFrom[1].Variables, want) || envFrom[1].Missing {
+		t.Errorf("Secret variables = %v (missing %v), want %v", envFrom[1].Variables, envFrom[1].Missing, want)
+	}
+	if !envFrom[2].Missing || envFrom[2].Variables != nil {
+		t.Errorf("missing Secret = %+v, want missing without variables", envFrom[2])
+	}
+
+	// A second pod with the same references is served from the cache
+	client.ClearActions()
+	again := []ContainerEnv{{Name: "app", EnvFrom: []EnvFromSource{{Kind: "ConfigMap", Name: "app-config"}, {Kind: "Secret", Name: "missing"}}}}
+	if err := resolver.expand("default", again); err != nil {
+		t.Fatalf("expand() error = %v", err)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("expand() made %d API calls for cached sources, want 0", len(actions))
+	}
+	if !again[0].EnvFrom[1].Missing {
+		t.Error("cached missing Secret is not marked missing")
+	}
+}
+
+func TestPriorityClassResolver(t *testing.T) {
+	newPriorityClass := func(name string, value int64, globalDefault bool) *unstructured.Unstructured {
+		class := &unstructured.Unstructured{Object: map[string]interface{}{
+			"value":            value,
+			"globalDefault":    globalDefault,
+			"preemptionPolicy": "PreemptLowerPriority",
+		}}
+		class.SetAPIVersion("scheduling.k8s.io/v1")
+		class.SetKind("PriorityClass")
+		class.SetName(name)
+		return class
+	}
+	client := newFakeDynamicClient(newPriorityClass("high", 100000, false), newPriorityClass("standard", 1000, true))
+	gets := 0
+	client.PrependReactor("get", "priorityclasses", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return false, nil, nil
+	})
+	resolver := newPriorityClassResolver(client)
+
+	for i := 0; i < 2; i++ {
+		info, err := resolver.resolve("high")
+		if err != nil {
+			t.Fatalf("resolve(high) error = %v", err)
+		}
+		if info.Value == nil || *info.Value != 100000 || info.PreemptionPolicy != "PreemptLowerPriority" || info.Missing {
+			t.Errorf("resolve(high) = %+v, want value 100000", info)
+		}
+	}
+	if gets != 1 {
+		t.Errorf("resolve(high) twice made %d requests, want 1", gets)
+	}
+
+	info, err := resolver.resolve("gone")
+	if err != nil {
+		t.Fatalf("resolve(gone) error = %v", err)
+	}
+	if !info.Missing || info.Value != nil {
+		t.Errorf("resolve(gone) = %+v, want missing", info)
+	}
+
+	// Resources that don't name a class get the globalDefault one
+	info, err = resolver.resolve("")
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if info == nil || info.Name != "standard" || !info.GlobalDefault {
+		t.Errorf("resolve() = %+v, want the globalDefault class standard", info)
+	}
+}
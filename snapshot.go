@@ -0,0 +1,336 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultSnapshotFileName returns the file name used when --snapshot-file is not passed
+func defaultSnapshotFileName(timestamp time.Time) string {
+	return fmt.Sprintf("getinfo-snapshot-%s.json", timestamp.UTC().Format("20060102T150405Z"))
+}
+
+// newSnapshot wraps the output of a command in a snapshot taken now
+func newSnapshot(cmdType, subCommand, resourceType string, output Output) Snapshot {
+	return Snapshot{
+		Timestamp:    now().UTC(),
+		Command:      cmdType,
+		SubCommand:   subCommand,
+		ResourceType: resourceType,
+		Output:       output,
+	}
+}
+
+// writeSnapshot writes the output of a command to a snapshot file
+func writeSnapshot(path string, snapshot Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling snapshot: %v", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing snapshot file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// readSnapshot reads a snapshot file written by writeSnapshot
+func readSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, fmt.Errorf("error reading snapshot file %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("error parsing snapshot file %s: %v", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// diffSnapshots compares two snapshots and reports added, removed and changed resources
+func diffSnapshots(oldSnapshot, newSnapshot Snapshot) SnapshotDiff {
+	diff := SnapshotDiff{}
+
+	oldItems := make(map[string]OutputItem)
+	for _, item := range oldSnapshot.Output.Items {
+		oldItems[outputItemKey(item)] = item
+	}
+	newItems := make(map[string]OutputItem)
+	for _, item := range newSnapshot.Output.Items {
+		newItems[outputItemKey(item)] = item
+	}
+
+	for key, newItem := range newItems {
+		oldItem, exists := oldItems[key]
+		if !exists {
+			diff.Added = append(diff.Added, key)
+			continue
+		}
+
+		if resourceDiff, changed := diffOutputItems(key, oldItem, newItem); changed {
+			diff.Changed = append(diff.Changed, resourceDiff)
+		}
+	}
+
+	for key := range oldItems {
+		if _, exists := newItems[key]; !exists {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+
+	// Sort for consistent output
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Resource < diff.Changed[j].Resource
+	})
+
+	return diff
+}
+
+// diffOutputItems compares the same resource in two snapshots
+// Labels and annotations are compared key by key, other fields as a whole
+func diffOutputItems(key string, oldItem, newItem OutputItem) (ResourceDiff, bool) {
+	resourceDiff := ResourceDiff{Resource: key}
+
+	if oldItem.Labels != nil || newItem.Labels != nil {
+		resourceDiff.Labels = diffStringMaps(derefStringMap(oldItem.Labels), derefStringMap(newItem.Labels))
+	}
+	if oldItem.Annotations != nil || newItem.Annotations != nil {
+		resourceDiff.Annotations = diffStringMaps(derefStringMap(oldItem.Annotations), derefStringMap(newItem.Annotations))
+	}
+
+	// Compare the remaining fields through their JSON representation
+	oldFields := outputItemFields(oldItem)
+	newFields := outputItemFields(newItem)
+	for field := range mergeKeys(oldFields, newFields) {
+		if !reflect.DeepEqual(oldFields[field], newFields[field]) {
+			resourceDiff.Fields = append(resourceDiff.Fields, field)
+		}
+	}
+	sort.Strings(resourceDiff.Fields)
+
+	changed := resourceDiff.Labels != nil || resourceDiff.Annotations != nil || len(resourceDiff.Fields) > 0
+	return resourceDiff, changed
+}
+
+// outputItemFields returns the JSON fields of an item except identity, labels and annotations
+func outputItemFields(item OutputItem) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fields
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fields
+	}
+
+	for _, field := range []string{"name", "namespace", "labels", "annotations"} {
+		delete(fields, field)
+	}
+	return fields
+}
+
+// mergeKeys returns the union of the keys of two maps
+func mergeKeys(a, b map[string]interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// derefStringMap returns the map behind a pointer, or nil
+func derefStringMap(m *map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	return *m
+}
+
+// diffStringMaps compares two string maps key by key, returning nil if they are equal
+func diffStringMaps(oldMap, newMap map[string]string) *MapDiff {
+	mapDiff := &MapDiff{}
+
+	for k, newValue := range newMap {
+		oldValue, exists := oldMap[k]
+		if !exists {
+			if mapDiff.Added == nil {
+				mapDiff.Added = make(map[string]string)
+			}
+			mapDiff.Added[k] = newValue
+		} else if oldValue != newValue {
+			if mapDiff.Changed == nil {
+				mapDiff.Changed = make(map[string]ValueChange)
+			}
+			mapDiff.Changed[k] = ValueChange{Old: oldValue, New: newValue}
+		}
+	}
+
+	for k, oldValue := range oldMap {
+		if _, exists := newMap[k]; !exists {
+			if mapDiff.Removed == nil {
+				mapDiff.Removed = make(map[string]string)
+			}
+			mapDiff.Removed[k] = oldValue
+		}
+	}
+
+	if mapDiff.Added == nil && mapDiff.Removed == nil && mapDiff.Changed == nil {
+		return nil
+	}
+	return mapDiff
+}
+
+// describeSnapshot returns a one-line description of a snapshot
+func describeSnapshot(snapshot Snapshot) string {
+	command := snapshot.Command
+	if snapshot.SubCommand != "" {
+		command += " " + snapshot.SubCommand
+	}
+	return fmt.Sprintf("%s (%s %s, %d items)", snapshot.Timestamp.Format(time.RFC3339), command, snapshot.ResourceType, len(snapshot.Output.Items))
+}
+
+// printSnapshotDiff outputs a snapshot diff as a human-readable report
+func printSnapshotDiff(diff SnapshotDiff, oldSnapshot, newSnapshot Snapshot) {
+	fmt.Printf("--- %s\n", describeSnapshot(oldSnapshot))
+	fmt.Printf("+++ %s\n", describeSnapshot(newSnapshot))
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+		fmt.Println("No changes")
+		return
+	}
+
+	for _, key := range diff.Added {
+		fmt.Printf("+ %s\n", key)
+	}
+	for _, key := range diff.Removed {
+		fmt.Printf("- %s\n", key)
+	}
+	for _, resourceDiff := range diff.Changed {
+		fmt.Printf("~ %s\n", resourceDiff.Resource)
+		printMapDiff("labels", resourceDiff.Labels)
+		printMapDiff("annotations", resourceDiff.Annotations)
+		if len(resourceDiff.Fields) > 0 {
+			fmt.Printf("    changed: %s\n", strings.Join(resourceDiff.Fields, ", "))
+		}
+	}
+}
+
+// printMapDiff outputs the key-level changes of a labels/annotations map
+func printMapDiff(name string, mapDiff *MapDiff) {
+	if mapDiff == nil {
+		return
+	}
+
+	fmt.Printf("    %s:\n", name)
+	for _, k := range sortedKeys(mapDiff.Added) {
+		fmt.Printf("      + %s=%s\n", k, mapDiff.Added[k])
+	}
+	for _, k := range sortedKeys(mapDiff.Removed) {
+		fmt.Printf("      - %s=%s\n", k, mapDiff.Removed[k])
+	}
+
+	changedKeys := make([]string, 0, len(mapDiff.Changed))
+	for k := range mapDiff.Changed {
+		changedKeys = append(changedKeys, k)
+	}
+	sort.Strings(changedKeys)
+	for _, k := range changedKeys {
+		fmt.Printf("      ~ %s: %s -> %s\n", k, mapDiff.Changed[k].Old, mapDiff.Changed[k].New)
+	}
+}
+
+// sortedKeys returns the keys of a string map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// handleSnapshotDiff handles the snapshot-diff command
+func handleSnapshotDiff(args []string) {
+	if len(args) == 0 || containsHelpFlag(args) {
+		printSnapshotDiffUsage()
+		os.Exit(0)
+	}
+
+	var files []string
+	var quiet bool
+	outputFormat := "text"
+	args = preprocessArgs(args)
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-o" || args[i] == "--output":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: flag needs an argument: %s\n", args[i])
+				os.Exit(1)
+			}
+			outputFormat = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--output="):
+			outputFormat = strings.TrimPrefix(args[i], "--output=")
+		case args[i] == "-q" || args[i] == "--quiet":
+			quiet = true
+		default:
+			files = append(files, args[i])
+		}
+	}
+
+	if len(files) != 2 {
+		fmt.Fprintf(os.Stderr, "Error: snapshot-diff requires exactly two snapshot files, got %d\n", len(files))
+		printSnapshotDiffUsage()
+		os.Exit(1)
+	}
+
+	oldSnapshot, err := readSnapshot(files[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	newSnapshot, err := readSnapshot(files[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if (oldSnapshot.Command != newSnapshot.Command || oldSnapshot.SubCommand != newSnapshot.SubCommand) && !quiet {
+		fmt.Fprintf(os.Stderr, "Warning: snapshots were taken with different commands, field changes may not be meaningful\n")
+	}
+
+	diff := diffSnapshots(oldSnapshot, newSnapshot)
+
+	switch strings.ToLower(outputFormat) {
+	case "text":
+		printSnapshotDiff(diff, oldSnapshot, newSnapshot)
+	case "json":
+		if err := writeJSON(os.Stdout, diff, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		if err := writeYAML(os.Stdout, diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling YAML: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported output format '%s'. Supported formats: text, json, yaml\n", outputFormat)
+		os.Exit(1)
+	}
+}
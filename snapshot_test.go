@@ -0,0 +1,76 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	stringMap := func(m map[string]string) *map[string]string { return &m }
+	oldSnapshot := Snapshot{Output: Output{Items: []OutputItem{
+		{Name: "web", Namespace: "default", Labels: stringMap(map[string]string{"app": "web", "tier": "frontend", "team": "a"})},
+		{Name: "db", Namespace: "default", Labels: stringMap(map[string]string{"app": "db"})},
+		{Name: "cache", Namespace: "default", Labels: stringMap(map[string]string{"app": "cache"})},
+		{Name: "web", Namespace: "prod", OwnerReferences: []OwnerReference{{Kind: "ReplicaSet", Name: "web-1"}}},
+	}}}
+	newSnapshot := Snapshot{Output: Output{Items: []OutputItem{
+		{Name: "web", Namespace: "default", Labels: stringMap(map[string]string{"app": "web", "tier": "backend", "version": "v2"})},
+		{Name: "db", Namespace: "default", Labels: stringMap(map[string]string{"app": "db"})},
+		{Name: "queue", Namespace: "default", Labels: stringMap(map[string]string{"app": "queue"})},
+		{Name: "web", Namespace: "prod", OwnerReferences: []OwnerReference{{Kind: "ReplicaSet", Name: "web-2"}}},
+	}}}
+
+	want := SnapshotDiff{
+		Added:   []string{"default/queue"},
+		Removed: []string{"default/cache"},
+		Changed: []ResourceDiff{
+			{Resource: "default/web", Labels: &MapDiff{
+				Added:   map[string]string{"version": "v2"},
+				Removed: map[string]string{"team": "a"},
+				Changed: map[string]ValueChange{"tier": {Old: "frontend", New: "backend"}},
+			}},
+			{Resource: "prod/web", Fields: []string{"ownerReferences"}},
+		},
+	}
+	if got := diffSnapshots(oldSnapshot, newSnapshot); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffSnapshots() = %+v, want %+v", got, want)
+	}
+
+	if got := diffSnapshots(oldSnapshot, oldSnapshot); !reflect.DeepEqual(got, SnapshotDiff{}) {
+		t.Errorf("diffSnapshots() of the same snapshot = %+v, want no changes", got)
+	}
+}
+
+func TestNewSnapshotUsesNow(t *testing.T) {
+	setNow(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+
+	snapshot := newSnapshot("labels", "", "pods", Output{})
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC); !snapshot.Timestamp.Equal(want) || snapshot.Timestamp.Location() != time.UTC {
+		t.Errorf("newSnapshot() timestamp = %v, want %v", snapshot.Timestamp, want)
+	}
+	if got, want := defaultSnapshotFileName(snapshot.Timestamp), "getinfo-snapshot-20240501T120000Z.json"; got != want {
+		t.Errorf("defaultSnapshotFileName() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteAndReadSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	snapshot := Snapshot{
+		Timestamp:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Command:      "labels",
+		ResourceType: "pods",
+		Output:       Output{Items: []OutputItem{{Name: "web", Namespace: "default"}}},
+	}
+	if err := writeSnapshot(path, snapshot); err != nil {
+		t.Fatalf("writeSnapshot() error = %v", err)
+	}
+	got, err := readSnapshot(path)
+	if err != nil {
+		t.Fatalf("readSnapshot() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, snapshot) {
+		t.Errorf("readSnapshot() = %+v, want %+v", got, snapshot)
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
+import "time"
+
 // OwnerReference represents a reference to an owner of a Kubernetes resource
 type OwnerReference struct {
-	Namespace string `json:"namespace,omitempty"`
-	Kind      string `json:"kind"`
-	Name      string `json:"name"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty" yaml:"uid,omitempty"`
+	// Controller marks the managing controller, at most one owner has it
+	Controller bool `json:"controller,omitempty" yaml:"controller,omitempty"`
 }
 
 // ContainerResources represents resource requests and limits for a single container
@@ -12,6 +18,172 @@ type ContainerResources struct {
 	Name     string                 `json:"name" yaml:"name"`
 	Requests map[string]interface{} `json:"requests,omitempty" yaml:"requests,omitempty"`
 	Limits   map[string]interface{} `json:"limits,omitempty" yaml:"limits,omitempty"`
+	// Pod resource claims the container uses (dynamic resource allocation)
+	Claims []interface{} `json:"claims,omitempty" yaml:"claims,omitempty"`
+	// Actual usage from the metrics API (--with-usage)
+	Usage map[string]interface{} `json:"usage,omitempty" yaml:"usage,omitempty"`
+}
+
+// PodDisruptionBudgetInfo represents a PodDisruptionBudget whose selector matches a resource's pods
+type PodDisruptionBudgetInfo struct {
+	Name               string      `json:"name" yaml:"name"`
+	MinAvailable       interface{} `json:"minAvailable,omitempty" yaml:"minAvailable,omitempty"`
+	MaxUnavailable     interface{} `json:"maxUnavailable,omitempty" yaml:"maxUnavailable,omitempty"`
+	CurrentHealthy     int64       `json:"currentHealthy" yaml:"currentHealthy"`
+	DesiredHealthy     int64       `json:"desiredHealthy" yaml:"desiredHealthy"`
+	DisruptionsAllowed int64       `json:"disruptionsAllowed" yaml:"disruptionsAllowed"`
+	ExpectedPods       int64       `json:"expectedPods" yaml:"expectedPods"`
+}
+
+// ContainerCommand represents the command and args of a single container
+type ContainerCommand struct {
+	Name    string   `json:"name" yaml:"name"`
+	Init    bool     `json:"init,omitempty" yaml:"init,omitempty"`
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+	Args    []string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// ContainerEnv represents the environment variables of a single container
+type ContainerEnv struct {
+	Name    string          `json:"name" yaml:"name"`
+	Init    bool            `json:"init,omitempty" yaml:"init,omitempty"`
+	Env     []EnvVar        `json:"env,omitempty" yaml:"env,omitempty"`
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty" yaml:"envFrom,omitempty"`
+}
+
+// EnvVar represents one env entry, set inline (Value) or from a reference (ValueFrom, e.g. secretKeyRef:db/password)
+type EnvVar struct {
+	Name      string `json:"name" yaml:"name"`
+	Value     string `json:"value,omitempty" yaml:"value,omitempty"`
+	ValueFrom string `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
+}
+
+// EnvFromSource represents a ConfigMap or Secret whose keys all become variables (envFrom)
+// Variables and Missing are only filled by --expand-refs, secret values are never read out
+type EnvFromSource struct {
+	Kind      string   `json:"kind" yaml:"kind"`
+	Name      string   `json:"name" yaml:"name"`
+	Prefix    string   `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Optional  bool     `json:"optional,omitempty" yaml:"optional,omitempty"`
+	Variables []string `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Missing   bool     `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+// ContainerHooks represents the postStart and preStop lifecycle hooks of a single container
+// Handlers are kept as in the spec (exec, httpGet, tcpSocket or sleep)
+type ContainerHooks struct {
+	Name      string                 `json:"name" yaml:"name"`
+	Init      bool                   `json:"init,omitempty" yaml:"init,omitempty"`
+	PostStart map[string]interface{} `json:"postStart,omitempty" yaml:"postStart,omitempty"`
+	PreStop   map[string]interface{} `json:"preStop,omitempty" yaml:"preStop,omitempty"`
+}
+
+// VolumeInfo represents one volume of a pod spec
+// Type is the volume source (e.g. persistentVolumeClaim, configMap, emptyDir), Source names what it refers to
+type VolumeInfo struct {
+	Name   string `json:"name" yaml:"name"`
+	Type   string `json:"type" yaml:"type"`
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// VolumeClaimTemplate represents one of the volumeClaimTemplates of a StatefulSet
+// Every replica gets its own PersistentVolumeClaim from it, named <template>-<statefulset>-<ordinal>
+type VolumeClaimTemplate struct {
+	Name             string   `json:"name" yaml:"name"`
+	StorageClassName string   `json:"storageClassName,omitempty" yaml:"storageClassName,omitempty"`
+	Storage          string   `json:"storage,omitempty" yaml:"storage,omitempty"`
+	AccessModes      []string `json:"accessModes,omitempty" yaml:"accessModes,omitempty"`
+}
+
+// ReadinessGate represents a custom readiness gate of a pod and the condition that satisfies it
+// Status is the status of the matching pod condition, "Missing" when the pod has no such condition yet
+// (the gate is then not satisfied). It is empty for pod templates, which have no status.
+type ReadinessGate struct {
+	ConditionType string `json:"conditionType" yaml:"conditionType"`
+	Status        string `json:"status,omitempty" yaml:"status,omitempty"`
+	Reason        string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// ManagedFieldsSummary lists the fields owned by one field manager (server-side apply)
+type ManagedFieldsSummary struct {
+	Manager     string `json:"manager" yaml:"manager"`
+	Operation   string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	Subresource string `json:"subresource,omitempty" yaml:"subresource,omitempty"`
+	// Fields as dotted paths two levels deep, e.g. spec.replicas or metadata.labels
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// IdentityInfo contains the service account and registry credentials used by a pod
+type IdentityInfo struct {
+	// Defaults to "default" when the pod spec doesn't set it, like the API server does
+	ServiceAccountName string `json:"serviceAccountName" yaml:"serviceAccountName"`
+	// Nil means the service account's own automount setting applies
+	AutomountServiceAccountToken *bool    `json:"automountServiceAccountToken,omitempty" yaml:"automountServiceAccountToken,omitempty"`
+	ImagePullSecrets             []string `json:"imagePullSecrets,omitempty" yaml:"imagePullSecrets,omitempty"`
+}
+
+// RolloutRevisionInfo tells whether a Deployment-owned pod belongs to the Deployment's latest revision
+type RolloutRevisionInfo struct {
+	Deployment     string `json:"deployment" yaml:"deployment"`
+	ReplicaSet     string `json:"replicaSet" yaml:"replicaSet"`
+	Revision       string `json:"revision" yaml:"revision"`
+	LatestRevision string `json:"latestRevision" yaml:"latestRevision"`
+	Current        bool   `json:"current" yaml:"current"`
+}
+
+// ReplicasInfo contains the desired and observed replica counts of a workload
+type ReplicasInfo struct {
+	Desired   int64 `json:"desired" yaml:"desired"`
+	Current   int64 `json:"current" yaml:"current"`
+	Ready     int64 `json:"ready" yaml:"ready"`
+	Available int64 `json:"available" yaml:"available"`
+}
+
+// ServiceInfo contains how a Service is exposed and which pods it routes to
+type ServiceInfo struct {
+	Type       string            `json:"type" yaml:"type"`
+	ClusterIP  string            `json:"clusterIP,omitempty" yaml:"clusterIP,omitempty"`
+	ClusterIPs []string          `json:"clusterIPs,omitempty" yaml:"clusterIPs,omitempty"`
+	Selector   map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Only set for NodePort and LoadBalancer Services
+	ExternalTrafficPolicy string `json:"externalTrafficPolicy,omitempty" yaml:"externalTrafficPolicy,omitempty"`
+}
+
+// FinalizersInfo contains the finalizers of a resource and whether they block its deletion
+type FinalizersInfo struct {
+	Finalizers        []string `json:"finalizers,omitempty" yaml:"finalizers,omitempty"`
+	DeletionTimestamp string   `json:"deletionTimestamp,omitempty" yaml:"deletionTimestamp,omitempty"`
+	// Deletion was requested but finalizers still hold the resource (stuck terminating)
+	Stuck bool `json:"stuck,omitempty" yaml:"stuck,omitempty"`
+}
+
+// NetworkInfo contains the DNS settings and extra /etc/hosts entries of a pod
+type NetworkInfo struct {
+	// Defaults to "ClusterFirst" when the pod spec doesn't set it, like the API server does
+	DNSPolicy   string                 `json:"dnsPolicy" yaml:"dnsPolicy"`
+	DNSConfig   map[string]interface{} `json:"dnsConfig,omitempty" yaml:"dnsConfig,omitempty"`
+	HostAliases []HostAlias            `json:"hostAliases,omitempty" yaml:"hostAliases,omitempty"`
+}
+
+// HostAlias is an /etc/hosts entry added to a pod
+type HostAlias struct {
+	IP        string   `json:"ip" yaml:"ip"`
+	Hostnames []string `json:"hostnames,omitempty" yaml:"hostnames,omitempty"`
+}
+
+// RevisionInfo contains the rollout revision and change cause of a Deployment or ReplicaSet
+type RevisionInfo struct {
+	Revision    string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	ChangeCause string `json:"changeCause,omitempty" yaml:"changeCause,omitempty"`
+}
+
+// LifecycleInfo contains pod lifecycle fields that affect restarts and eviction
+type LifecycleInfo struct {
+	RestartPolicy                 string `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty"`
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" yaml:"terminationGracePeriodSeconds,omitempty"`
+	// Job-level deadline for Jobs and CronJobs, pod-level deadline otherwise
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" yaml:"activeDeadlineSeconds,omitempty"`
 }
 
 // SchedulingInfo contains scheduling-related fields from a pod spec
@@ -23,6 +195,7 @@ type SchedulingInfo struct {
 	TopologySpreadConstraints []interface{}          `json:"topologySpreadConstraints,omitempty" yaml:"topologySpreadConstraints,omitempty"`
 	ResourceRequests          map[string]interface{} `json:"resourceRequests,omitempty" yaml:"resourceRequests,omitempty"`
 	ResourceLimits            map[string]interface{} `json:"resourceLimits,omitempty" yaml:"resourceLimits,omitempty"`
+	Overhead                  map[string]interface{} `json:"overhead,omitempty" yaml:"overhead,omitempty"`
 	SchedulerName             string                 `json:"schedulerName,omitempty" yaml:"schedulerName,omitempty"`
 	PriorityClassName         string                 `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
 	Priority                  *int32                 `json:"priority,omitempty" yaml:"priority,omitempty"`
@@ -35,24 +208,239 @@ type SchedulingInfo struct {
 
 // OutputItem represents a single resource in the output
 type OutputItem struct {
-	Name            string             `json:"name"`
-	Namespace       string             `json:"namespace,omitempty"`
-	Labels          *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Annotations     *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	OwnerReferences []OwnerReference   `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
-	Scheduling      *SchedulingInfo    `json:"scheduling,omitempty" yaml:"scheduling,omitempty"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// ResourceType is the kind.group used by name output (e.g. deployment.apps), never serialized
+	ResourceType string `json:"-" yaml:"-"`
+	// AnnotationGroup is the value of the --group-by-annotation annotation ("<none>" when unset), never serialized
+	AnnotationGroup string `json:"-" yaml:"-"`
+	// CreationTimestamp is only used for table coloring and never serialized
+	CreationTimestamp time.Time          `json:"-" yaml:"-"`
+	Labels            *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations       *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// Labels of the resource's namespace, kept apart from its own labels (--inherit-namespace-labels)
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty" yaml:"namespaceLabels,omitempty"`
+	OwnerReferences []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	// Kind and name of the controller owner, flattened for simple templates like {.items[*].ownerName} (owner command)
+	OwnerKind string `json:"ownerKind,omitempty" yaml:"ownerKind,omitempty"`
+	OwnerName string `json:"ownerName,omitempty" yaml:"ownerName,omitempty"`
+	// Mirror pod of a static pod (labels, annotations and owner commands)
+	Static bool `json:"static,omitempty" yaml:"static,omitempty"`
+	// Current or previous Deployment revision of the pod (owner --since-revision)
+	Rollout    *RolloutRevisionInfo `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+	Scheduling *SchedulingInfo      `json:"scheduling,omitempty" yaml:"scheduling,omitempty"`
+	// PodDisruptionBudgets matching the resource's pods (pdb command)
+	PodDisruptionBudgets []PodDisruptionBudgetInfo `json:"podDisruptionBudgets,omitempty" yaml:"podDisruptionBudgets,omitempty"`
+	// Container commands and args (command command)
+	Commands []ContainerCommand `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// Container environment variables (env command)
+	Env []ContainerEnv `json:"env,omitempty" yaml:"env,omitempty"`
+	// Container postStart and preStop hooks (hooks command)
+	Hooks []ContainerHooks `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	// Pod volumes and StatefulSet volumeClaimTemplates (volumes command)
+	Volumes              []VolumeInfo          `json:"volumes,omitempty" yaml:"volumes,omitempty"`
+	VolumeClaimTemplates []VolumeClaimTemplate `json:"volumeClaimTemplates,omitempty" yaml:"volumeClaimTemplates,omitempty"`
+	// Readiness gates and the status of their conditions (readiness command)
+	ReadinessGates []ReadinessGate `json:"readinessGates,omitempty" yaml:"readinessGates,omitempty"`
+	// Restart policy and grace periods (lifecycle command)
+	Lifecycle *LifecycleInfo `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	// Service account and pull secrets (identity command)
+	Identity *IdentityInfo `json:"identity,omitempty" yaml:"identity,omitempty"`
+	// Desired and observed replicas (replicas command)
+	Replicas *ReplicasInfo `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	// Type, cluster IPs and selector (service command)
+	Service *ServiceInfo `json:"service,omitempty" yaml:"service,omitempty"`
+	// DNS policy, DNS config and host aliases (network command)
+	Network *NetworkInfo `json:"network,omitempty" yaml:"network,omitempty"`
+	// Finalizers and deletion timestamp (finalizers command)
+	Finalizers *FinalizersInfo `json:"finalizers,omitempty" yaml:"finalizers,omitempty"`
+	// Rollout revision annotations (revision command)
+	Revision *RevisionInfo `json:"revision,omitempty" yaml:"revision,omitempty"`
+	// Where the pod spec was read from, e.g. spec.template.spec (--show-spec-path)
+	SpecPath string `json:"specPath,omitempty" yaml:"specPath,omitempty"`
+	// Values of the --json-pointer fields, keyed by pointer
+	Fields map[string]interface{} `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Which manager owns which fields (--managed-fields-summary)
+	ManagedFields []ManagedFieldsSummary `json:"managedFields,omitempty" yaml:"managedFields,omitempty"`
 	// Specific fields for scheduling subcommands
 	Tolerations               []interface{}          `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
 	Affinity                  map[string]interface{} `json:"affinity,omitempty" yaml:"affinity,omitempty"`
 	NodeSelector              map[string]string      `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
 	Resources                 []ContainerResources   `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Overhead                  map[string]interface{} `json:"overhead,omitempty" yaml:"overhead,omitempty"`
+	ResourceClaims            []interface{}          `json:"resourceClaims,omitempty" yaml:"resourceClaims,omitempty"`
 	TopologySpreadConstraints []interface{}          `json:"topologySpreadConstraints,omitempty" yaml:"topologySpreadConstraints,omitempty"`
 	Priority                  map[string]interface{} `json:"priority,omitempty" yaml:"priority,omitempty"`
 	Runtime                   map[string]interface{} `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+	// PriorityClass the priority comes from, looked up with --resolve-priority
+	PriorityClass *PriorityClassInfo `json:"priorityClass,omitempty" yaml:"priorityClass,omitempty"`
+}
+
+// PriorityClassInfo represents the PriorityClass that gives a pod its priority (--resolve-priority)
+type PriorityClassInfo struct {
+	Name             string `json:"name" yaml:"name"`
+	Value            *int64 `json:"value,omitempty" yaml:"value,omitempty"`
+	GlobalDefault    bool   `json:"globalDefault,omitempty" yaml:"globalDefault,omitempty"`
+	PreemptionPolicy string `json:"preemptionPolicy,omitempty" yaml:"preemptionPolicy,omitempty"`
+	// Missing is set when the named class doesn't exist, new pods naming it are rejected
+	Missing bool `json:"missing,omitempty" yaml:"missing,omitempty"`
 }
 
+// OwnerKindCount represents how many resources are owned by a given owner kind
+type OwnerKindCount struct {
+	Kind  string `json:"kind" yaml:"kind"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// OwnerKindCounts represents the output of owner --count-by-kind
+type OwnerKindCounts struct {
+	Counts []OwnerKindCount `json:"counts" yaml:"counts"`
+}
+
+// LabelValueCount represents how many resources carry a label with a given value
+type LabelValueCount struct {
+	Value string `json:"value" yaml:"value"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+// LabelValueCounts represents the output of labels --count-unique
+type LabelValueCounts struct {
+	Label string `json:"label" yaml:"label"`
+	// Distinct is the number of different values (the cardinality of the label)
+	Distinct int               `json:"distinct" yaml:"distinct"`
+	Values   []LabelValueCount `json:"values" yaml:"values"`
+	// Missing counts the resources without the label
+	Missing int `json:"missing" yaml:"missing"`
+}
+
+// LabelKeyUsage represents how many resources of each compared namespace carry a label key
+type LabelKeyUsage struct {
+	Key string `json:"key" yaml:"key"`
+	// Counts follows the order of NamespaceLabelDiff.Namespaces
+	Counts []int `json:"counts" yaml:"counts"`
+}
+
+// NamespaceLabelDiff represents the output of labels --diff-namespace
+type NamespaceLabelDiff struct {
+	Namespaces []string `json:"namespaces" yaml:"namespaces"`
+	// Resources is the number of resources listed in each namespace
+	Resources []int `json:"resources" yaml:"resources"`
+	// Common lists the keys used in both namespaces
+	Common []LabelKeyUsage `json:"common" yaml:"common"`
+	// OnlyIn lists the keys used in a single namespace, by namespace
+	OnlyIn map[string][]LabelKeyUsage `json:"onlyIn" yaml:"onlyIn"`
+}
+
+// OwnerCount represents an owner shared by one or more resources
+type OwnerCount struct {
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Name       string `json:"name" yaml:"name"`
+	Count      int    `json:"count" yaml:"count"`
+}
+
+// IdenticalMapCount represents how many resources carry exactly the same labels or annotations
+type IdenticalMapCount struct {
+	Values map[string]string `json:"values" yaml:"values"`
+	Count  int               `json:"count" yaml:"count"`
+	// Example is the first resource found with these values (namespace/name)
+	Example string `json:"example" yaml:"example"`
+}
+
+// IdenticalMapCounts represents the output of labels and annotations --dedupe-identical
+type IdenticalMapCounts struct {
+	Sets []IdenticalMapCount `json:"sets" yaml:"sets"`
+}
+
+// OwnerCounts represents the output of owner --dedupe
+type OwnerCounts struct {
+	Owners []OwnerCount `json:"owners" yaml:"owners"`
+}
+
+// APIResourceInfo describes one resource type served by the cluster (resources command)
+type APIResourceInfo struct {
+	Name       string   `json:"name" yaml:"name"`
+	ShortNames []string `json:"shortNames,omitempty" yaml:"shortNames,omitempty"`
+	APIVersion string   `json:"apiVersion" yaml:"apiVersion"`
+	Namespaced bool     `json:"namespaced" yaml:"namespaced"`
+	Kind       string   `json:"kind" yaml:"kind"`
+	Verbs      []string `json:"verbs" yaml:"verbs"`
+}
+
+// APIResources represents the output of the resources command
+type APIResources struct {
+	Resources []APIResourceInfo `json:"resources" yaml:"resources"`
+}
+
+// outputSchemaVersion identifies the shape of the json and yaml output, so tools reading it can check what they get.
+// Bump it when a field is renamed, removed or changes type; new optional fields keep the version.
+const outputSchemaVersion = "getinfo.dev/v1"
+
 // Output represents the complete output structure
 type Output struct {
-	Items []OutputItem `json:"items"`
+	// Set for json and yaml output only
+	SchemaVersion string       `json:"schemaVersion,omitempty" yaml:"schemaVersion,omitempty"`
+	Items         []OutputItem `json:"items"`
+}
+
+// NamespacedOutput is the alternate shape of Output for --nest-by-namespace,
+// with one list of items per namespace
+type NamespacedOutput struct {
+	SchemaVersion string            `json:"schemaVersion,omitempty" yaml:"schemaVersion,omitempty"`
+	Namespaces    map[string]Output `json:"namespaces"`
 }
 
+// OtelResource holds the OpenTelemetry resource attributes of one item (-o otel)
+type OtelResource struct {
+	Attributes map[string]string `json:"attributes"`
+}
+
+// OtelOutput represents the output of -o otel
+type OtelOutput struct {
+	Resources []OtelResource `json:"resources"`
+}
+
+// WatchEvent is one line of --watch output: the change type (ADDED, MODIFIED, DELETED) and the extracted item
+type WatchEvent struct {
+	Event string     `json:"event"`
+	Item  OutputItem `json:"item"`
+}
+
+// Snapshot is the content of a snapshot file written by the snapshot command
+type Snapshot struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Command      string    `json:"command"`
+	SubCommand   string    `json:"subCommand,omitempty" yaml:"subCommand,omitempty"`
+	ResourceType string    `json:"resourceType"`
+	Output       Output    `json:"output"`
+}
+
+// SnapshotDiff is the result of comparing two snapshots
+type SnapshotDiff struct {
+	Added   []string       `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed []string       `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Changed []ResourceDiff `json:"changed,omitempty" yaml:"changed,omitempty"`
+}
+
+// ResourceDiff describes the changes of a single resource between two snapshots
+type ResourceDiff struct {
+	Resource    string   `json:"resource" yaml:"resource"`
+	Labels      *MapDiff `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations *MapDiff `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// Other top-level fields that changed (e.g. ownerReferences, scheduling)
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// MapDiff describes the key-level changes of a labels or annotations map
+type MapDiff struct {
+	Added   map[string]string      `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed map[string]string      `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Changed map[string]ValueChange `json:"changed,omitempty" yaml:"changed,omitempty"`
+}
+
+// ValueChange represents a value that changed between two snapshots
+type ValueChange struct {
+	Old string `json:"old" yaml:"old"`
+	New string `json:"new" yaml:"new"`
+}
@@ -10,11 +10,27 @@ func printUsage() {
 	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo <command> [subcommand] <resource-type> [resource-name...] [flags]
 
 Commands:
-  labels       List labels of resources
-  annotations  List annotations of resources
-  owner        List ownerReferences of resources
-  scheduling   List scheduling-related fields (nodeSelector, affinity, tolerations, etc.)
-  completion   Generate shell completion scripts (bash, zsh, fish)
+  labels         List labels of resources
+  annotations    List annotations of resources
+  owner          List ownerReferences of resources
+  pdb            List PodDisruptionBudgets protecting resources
+  command        List container commands and args
+  hooks          List postStart and preStop hooks of containers
+  volumes        List volumes and StatefulSet volumeClaimTemplates
+  readiness      List readiness gates and whether their conditions are met
+  env            List container environment variables and envFrom sources
+  lifecycle      List restartPolicy and termination/deadline settings
+  revision       List rollout revision and change-cause of Deployments/ReplicaSets
+  identity       List serviceAccountName, token automount and imagePullSecrets
+  replicas       List desired, current, ready and available replicas of workloads
+  service        List type, cluster IP, selector and externalTrafficPolicy of Services
+  finalizers     List finalizers and deletionTimestamp (find resources stuck terminating)
+  network        List dnsPolicy, dnsConfig and hostAliases of pods
+  scheduling     List scheduling-related fields (nodeSelector, affinity, tolerations, etc.)
+  resources      List the resource types served by the cluster (like kubectl api-resources)
+  snapshot       Save the output of a command to a timestamped file
+  snapshot-diff  Compare two snapshot files
+  completion     Generate shell completion scripts (bash, zsh, fish)
 
 Scheduling Subcommands (optional):
   tolerations       List only tolerations
@@ -26,23 +42,79 @@ Scheduling Subcommands (optional):
   runtime           List only runtime-related fields (runtimeClassName, hostNetwork, etc.)
 
 Flags:
-  -n, --namespace <namespace>      Specify namespace
+  -n, --namespace <namespace>      Specify namespace, or several separated by commas (-n staging,prod)
   -A, --all-namespaces             All namespaces
+      --exclude-namespaces <list>  Comma-separated namespaces to leave out (e.g., with -A)
+      --no-system                  Leave out kube-system, kube-public and kube-node-lease
+      --no-fallback-namespace      Fail with "namespace required" instead of using the kubeconfig namespace
+      --show-namespace-source      Print the namespace queried and where it came from to stderr (also with -v 1)
+      --context <name>             Kubeconfig context to use (default: current context)
+      --token-file <file>          Authenticate with the bearer token in a file (reloaded when rotated)
+      --context-prefix             Prefix names with the context, e.g. staging/pod/web (-o name)
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format: json, yaml (default), table (owner only)
-  -c, --color                      Colorize JSON output
+      --glob                       Match names with *, ? or [ against a list of the resources (e.g. 'web-*')
+      --managed-by <tool>          Only resources with app.kubernetes.io/managed-by=<tool> (e.g., Helm)
+      --annotation-value-regex <key>=<pattern>
+                                   Only resources whose annotation value matches the regexp (e.g. 'checksum/config=^3f2a')
+      --field-selector <selector>  Field selector (e.g., --field-selector status.phase=Running)
+      --raw-field-selector <sel>   Field selector passed verbatim to the API server (no validation)
+      --scope <scope>              Types included by the 'all' resource type: namespaced, cluster, all (default)
+      --include-unavailable-groups Retry API groups that fail discovery before skipping them
+      --from-cache                 List from the API server's watch cache (may be slightly stale)
+      --concurrency <n>            Query at most n namespaces at once with -n a,b (default: no limit)
+      --non-empty                  Only show resources where the command's field is set (e.g. with tolerations)
+  -w, --watch                      Stream changes as ADDED/MODIFIED/DELETED events, one per line (-o jsonl)
+      --watch-timeout <duration>   Stop watching after this long and exit with 0 (e.g. 2m)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format: json, yaml (default), table (owner, pdb, command, lifecycle,
+                                   revision, identity, replicas, service, finalizers, network, hooks,
+                                   volumes, readiness, env, scheduling), csv, tsv, jsonl, name, otel, jsonpath=<template>, go-template=<template>
+  -c, --color                      Colorize JSON and table output
+      --legend                     With -c, print a line explaining the colors on stderr
+  -v, --verbosity <level>          Log API requests to stderr (e.g., -v 6, up to -v 9 for bodies)
+  -q, --quiet                      Only print the data and errors, no warnings or notes on stderr
+      --strict                     Fail on unknown flags and on flags after the resource names (read as names otherwise)
+      --strict-exit-codes          Exit with 2 (no results), 3 (not found), 4 (forbidden), 5 (connection error)
+                                   or 6 (invalid flags)
+      --as-map                     Output an object keyed by namespace/name (json, yaml)
+      --nest-by-namespace          Output items nested under their namespace (json, yaml)
+      --unwrap-single              Output a single result as the item itself, without items: (json, yaml)
+      --managed-fields-summary     Show which field manager owns which fields (json, yaml)
+      --show-spec-path             Show where the pod spec was read from, e.g. spec.template.spec (json, yaml, jsonl)
+      --json-pointer <pointer>     Add the field at a JSON pointer, e.g. /metadata/annotations/example.com~1owner
+                                   (json, yaml, jsonl, templates), may be repeated
+      --group-by-namespace         Group table rows by namespace (with -A)
+      --group-by-annotation <key>  Group table rows by the value of an annotation (e.g. owner-team)
+      --wide                       Expand summarized table cells (e.g. scheduling affinity rules)
+      --max-col-width <n>          Shorten table cells to n characters, ending with … (names are kept whole)
+      --abbrev-namespace           Shorten namespace prefixes shared by several namespaces (team-payments-prod
+                                   -> t-p-prod) and print a legend below the table
+      --excel-compat               Write a UTF-8 BOM and CRLF line endings (csv, tsv) for Excel on Windows
   -h, --help                       Show help
 
+Configuration:
+  KUBECTL_GETINFO_OUTPUT                  Default output format when -o is not passed
+  ~/.config/kubectl-getinfo/config.yaml   Config file with defaults: output, color, maxColWidth (table),
+                                          excludeNamespaces (with -A), concurrency
+
 Examples:
   kubectl getinfo labels pods pod1 pod2
   kubectl getinfo annotations nodes -l env=prod
   kubectl getinfo owner pods
   kubectl getinfo owner pods -o table
+  kubectl getinfo pdb deployments -n prod
+  kubectl getinfo command deployments -o table
   kubectl getinfo scheduling pods
   kubectl getinfo scheduling tolerations pods
   kubectl getinfo scheduling affinity pods -n kube-system
   kubectl getinfo labels deployments -n kube-system -o yaml
+  kubectl getinfo labels deployments -A --managed-by Helm
+  kubectl getinfo labels deployments,statefulsets -A
+  kubectl getinfo annotations all -A --scope namespaced
   kubectl getinfo labels pods -o json -c
+  kubectl get pods -o json | kubectl getinfo labels -F -
+  kubectl getinfo snapshot labels pods -A
+  kubectl getinfo snapshot-diff before.json after.json
 
 Use "kubectl getinfo <command> --help" for more information about a command.
 `)
@@ -64,13 +136,22 @@ Examples:
   kubectl getinfo labels deployments -l app=nginx     # List labels of deployments with label app=nginx
   kubectl getinfo labels pods -o json                  # Output in JSON format
   kubectl getinfo labels pods -o yaml                  # Output in YAML format
+  kubectl getinfo labels pods -A --count-unique app.kubernetes.io/version -o table   # Distinct versions running
+  kubectl getinfo labels deployments -n staging,prod --diff-namespace -o table       # Compare label keys of two namespaces
+  kubectl getinfo labels pods -A -L app,app.kubernetes.io/version -o table            # One column per label
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
   -o, --output <format>            Output format (json, yaml). Default: yaml
   -c, --color                      Colorize JSON output
+      --inherit-namespace-labels   Also show the labels of each resource's namespace
+  -L, --label-columns <keys>       Show the comma-separated label keys as one column each (table, csv, tsv)
+      --count-unique <key>         Count the distinct values of a label (json, yaml, table)
+      --diff-namespace             Compare the label keys used in the two namespaces given with -n (json, yaml, table)
+      --dedupe-identical           Collapse resources with identical labels and count them (json, yaml, table)
   -h, --help                       Show help
 `)
 	case "annotations":
@@ -84,13 +165,17 @@ Examples:
   kubectl getinfo annotations pods -A                  # List annotations of all pods in all namespaces
   kubectl getinfo annotations services -n default     # List annotations of services in default namespace
   kubectl getinfo annotations deployments -o json     # Output in JSON format
+  kubectl getinfo annotations pods --redact 'vault.hashicorp.com/,*token*'   # Hide sensitive values
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
   -o, --output <format>            Output format (json, yaml). Default: yaml
   -c, --color                      Colorize JSON output
+      --redact <keys>              Replace values of matching keys with REDACTED (exact, prefix/ or glob*)
+      --dedupe-identical           Collapse resources with identical annotations and count them (json, yaml, table)
   -h, --help                       Show help
 `)
 	case "owner":
@@ -104,13 +189,301 @@ Examples:
   kubectl getinfo owner pods -A                        # List owner references of all pods in all namespaces
   kubectl getinfo owner replicasets -n kube-system    # List owner references of replicasets
   kubectl getinfo owner pods -o yaml                   # Output in YAML format
+  kubectl getinfo owner pods --full-gvk                # Show owners as apiVersion/kind (e.g., apps/v1/ReplicaSet)
+  kubectl getinfo owner pods -A --count-by-kind        # Count pods per owner kind (ReplicaSet, Job, static, ...)
+  kubectl getinfo owner pods --dedupe -o table         # One row per owner with the number of pods it owns
+  kubectl getinfo owner pods --since-revision          # Find pods left over from previous rollouts
+  kubectl getinfo owner pods --wide                    # Add the controller flag and owner UID
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
   -o, --output <format>            Output format (json, yaml, table). Default: table
-  -c, --color                      Colorize JSON output
+  -c, --color                      Colorize JSON and table output
+      --full-gvk                   Show owner apiVersion/kind instead of kind only (table)
+      --count-by-kind              Count resources per owner kind instead of listing them
+      --dedupe                     Collapse identical owners and count the resources sharing them
+      --since-revision             Tell whether Deployment pods belong to the current or a previous revision
+      --wide                       Add CONTROLLER and OWNER UID columns (table)
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "pdb":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo pdb <resource-type> [resource-name...] [flags]
+
+List the PodDisruptionBudgets whose selector matches the pods of Kubernetes resources, with their
+minAvailable/maxUnavailable settings and current status. For workloads (deployments, statefulsets, etc.)
+the pod template labels are matched. Resources without a matching PDB are not protected against voluntary disruptions.
+
+Examples:
+  kubectl getinfo pdb pods                             # List PDBs matching all pods in current namespace
+  kubectl getinfo pdb deployments web                  # List PDBs protecting the web deployment
+  kubectl getinfo pdb statefulsets -A                  # List PDBs of all statefulsets in all namespaces
+  kubectl getinfo pdb pods -l app=nginx -o yaml        # Output in YAML format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -o, --output <format>            Output format (json, yaml, table). Default: table
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "command":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo command <resource-type> [resource-name...] [flags]
+
+List the command and args of each container of Kubernetes resources, useful to audit entrypoint overrides.
+Init containers are included and marked. Containers without command/args run the image entrypoint.
+
+Examples:
+  kubectl getinfo command pods                         # List container commands of all pods in current namespace
+  kubectl getinfo command deployments -A -o table      # List container commands of all deployments as a table
+  kubectl getinfo command pods pod1 -o json            # Output in JSON format (command/args arrays preserved)
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "hooks":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo hooks <resource-type> [resource-name...] [flags]
+
+List the postStart and preStop lifecycle hooks of each container, useful to audit graceful shutdown.
+Init containers are included and marked (they run hooks as sidecars). Containers without hooks show <none>.
+
+Examples:
+  kubectl getinfo hooks pods                           # List container hooks of all pods in current namespace
+  kubectl getinfo hooks deployments -A -o table        # Find deployments without a preStop hook
+  kubectl getinfo hooks pods pod1 -o json              # Output in JSON format (handlers as in the spec)
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "env":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo env <resource-type> [resource-name...] [flags]
+
+List the environment variables of every container (init containers first): inline values, references
+(secretKeyRef, configMapKeyRef, fieldRef, resourceFieldRef) and the ConfigMaps and Secrets loaded with envFrom.
+With --expand-refs, the envFrom sources are fetched and the variables they define are listed by name.
+Secret values are never shown.
+
+Examples:
+  kubectl getinfo env pods                             # List the environment of all pods in current namespace
+  kubectl getinfo env deployments -o table             # One row per variable
+  kubectl getinfo env deployments --expand-refs        # Include the variables loaded from ConfigMaps and Secrets
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --expand-refs                List the variables of the ConfigMaps and Secrets referenced by envFrom
+  -h, --help                       Show help
+`)
+	case "readiness":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo readiness <resource-type> [resource-name...] [flags]
+
+List the custom readiness gates of pods and the status of the condition each gate waits for
+(True, False, Unknown or Missing when the condition isn't set yet). Pod templates show the gates only.
+
+Examples:
+  kubectl getinfo readiness pods                       # List readiness gates of all pods in current namespace
+  kubectl getinfo readiness pods -A -o table           # Find pods waiting for a readiness gate
+  kubectl getinfo readiness pods pod1 -o json          # Output in JSON format (with reason and message)
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "volumes":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo volumes <resource-type> [resource-name...] [flags]
+
+List the volumes of the pod spec with their type and source (claim, ConfigMap, Secret, path or CSI driver).
+StatefulSets also list their volumeClaimTemplates with requested size, access modes and storage class.
+
+Examples:
+  kubectl getinfo volumes pods                         # List volumes of all pods in current namespace
+  kubectl getinfo volumes statefulsets -A -o table     # Audit the claim templates of all StatefulSets
+  kubectl getinfo volumes deployments web -o json      # Output in JSON format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "revision":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo revision <resource-type> [resource-name...] [flags]
+
+List the rollout revision (deployment.kubernetes.io/revision) and change cause (kubernetes.io/change-cause)
+of Deployments and ReplicaSets, to correlate ReplicaSets and their pods with the rollout history.
+
+Examples:
+  kubectl getinfo revision deployments                 # List revisions of all deployments in current namespace
+  kubectl getinfo revision replicasets -o table        # Match ReplicaSets to rollout revisions
+  kubectl getinfo revision deployments web -o json     # Output in JSON format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "identity":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo identity <resource-type> [resource-name...] [flags]
+
+List the identity of pods: serviceAccountName (default when unset), automountServiceAccountToken
+and imagePullSecrets. Useful to find pods running as the default service account or mounting API tokens.
+
+Examples:
+  kubectl getinfo identity pods                        # List identity of all pods in current namespace
+  kubectl getinfo identity deployments -A -o table     # Find workloads using the default service account
+  kubectl getinfo identity pods pod1 -o json           # Output in JSON format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "replicas":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo replicas <resource-type> [resource-name...] [flags]
+
+List the desired replicas (spec.replicas) and the current, ready and available replicas (status)
+of Deployments, StatefulSets and ReplicaSets. For DaemonSets the scheduled pod counts are used.
+
+Examples:
+  kubectl getinfo replicas deployments                 # List replicas of all deployments in current namespace
+  kubectl getinfo replicas statefulsets -A -o table    # Scaling view of all statefulsets
+  kubectl getinfo replicas deployments web -o json     # Output in JSON format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "service":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo service <resource-type> [resource-name...] [flags]
+
+List how Services are exposed: spec.type, spec.clusterIP(s), spec.selector and
+spec.externalTrafficPolicy (NodePort and LoadBalancer Services only).
+
+Examples:
+  kubectl getinfo service services                     # List services in current namespace
+  kubectl getinfo service svc -A -o table              # Type, cluster IP and selector of all services
+  kubectl getinfo service services web -o json        # Output in JSON format, including clusterIPs
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "finalizers":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo finalizers <resource-type> [resource-name...] [flags]
+
+List metadata.finalizers and metadata.deletionTimestamp of any resource. A resource with a
+deletionTimestamp that still holds finalizers is stuck terminating (shown in red with -c).
+
+Examples:
+  kubectl getinfo finalizers pvc -o table              # List finalizers of PVCs in current namespace
+  kubectl getinfo finalizers pvc -A -o table -c        # Highlight stuck PVCs in all namespaces
+  kubectl getinfo finalizers pods web -o json          # Output in JSON format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "network":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo network <resource-type> [resource-name...] [flags]
+
+List the DNS and hosts setup of pods: dnsPolicy (ClusterFirst when unset), dnsConfig
+(nameservers, searches, options) and hostAliases (extra /etc/hosts entries).
+
+Examples:
+  kubectl getinfo network pods                         # List DNS settings of all pods in current namespace
+  kubectl getinfo network deployments -A -o table      # Find workloads with custom DNS or host aliases
+  kubectl getinfo network pods web -o json             # Output in JSON format
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
+  -h, --help                       Show help
+`)
+	case "lifecycle":
+		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo lifecycle <resource-type> [resource-name...] [flags]
+
+List pod lifecycle settings that affect restarts and eviction: restartPolicy,
+terminationGracePeriodSeconds and activeDeadlineSeconds (the Job-level deadline for Jobs and CronJobs).
+
+Examples:
+  kubectl getinfo lifecycle pods                       # List lifecycle settings of all pods in current namespace
+  kubectl getinfo lifecycle deployments -A -o table    # List lifecycle settings of all deployments as a table
+  kubectl getinfo lifecycle jobs -o json               # Output in JSON format, including activeDeadlineSeconds
+
+Flags:
+  -n, --namespace <namespace>      Specify namespace
+  -A, --all-namespaces             All namespaces
+  -l, --selector <selector>        Label selector (e.g., -l app=nginx)
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
+  -c, --color                      Colorize JSON and table output
+      --group-by-namespace         Group table rows by namespace (with -A)
   -h, --help                       Show help
 `)
 	}
@@ -151,13 +524,19 @@ Examples:
   kubectl getinfo scheduling resources pods            # List only resource requests/limits
   kubectl getinfo scheduling pods -o json              # Output in JSON format
   kubectl getinfo scheduling pods -o yaml              # Output in YAML format
+  kubectl getinfo scheduling pods --explain            # Describe what each field means below the values
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
+      --wide                       Summarize affinity rules in table output instead of "present"
+      --compact-affinity           Prune empty affinity branches and empty arrays
+      --allow-missing-template     Skip resources without a pod spec (e.g. services) without a note
+      --explain                    Describe what each field means below the output (yaml, table)
   -h, --help                       Show help
 
 Use "kubectl getinfo scheduling <subcommand> --help" for more information about a subcommand.
@@ -182,7 +561,8 @@ Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
   -h, --help                       Show help
 `)
@@ -196,13 +576,19 @@ Examples:
   kubectl getinfo scheduling affinity pods -A                    # List affinity of all pods in all namespaces
   kubectl getinfo scheduling affinity deployments -n prod       # List affinity of deployments in prod
   kubectl getinfo scheduling affinity pods -o json               # Output in JSON format
+  kubectl getinfo scheduling affinity pods --compact-affinity    # Show only populated affinity rules
+  kubectl getinfo scheduling affinity pods -o table --wide       # One-line summary of the rules
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
+      --wide                       Summarize affinity rules in table output instead of "present"
+      --compact-affinity           Prune empty affinity branches and empty arrays
+      --allow-missing-template     Skip resources without a pod spec (e.g. services) without a note
   -h, --help                       Show help
 `)
 	case "nodeselector":
@@ -220,27 +606,36 @@ Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
   -h, --help                       Show help
 `)
 	case "resources":
 		fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo scheduling resources <resource-type> [resource-name...] [flags]
 
-List resource requests and limits of Kubernetes resources. Shows CPU and memory requests/limits for containers.
+List resource requests and limits of Kubernetes resources. Shows CPU and memory requests/limits for containers,
+and the pod overhead (spec.overhead) set by the RuntimeClass, which is scheduled on top of the container requests.
+Resource claims for dynamic resource allocation (spec.resourceClaims, e.g. GPUs) are listed with the containers
+that use them (resources.claims), table output shows the claim names in a CLAIMS column.
 
 Examples:
   kubectl getinfo scheduling resources pods                      # List resources of all pods
   kubectl getinfo scheduling resources pods -A                   # List resources of all pods in all namespaces
   kubectl getinfo scheduling resources deployments -n prod      # List resources of deployments in prod
   kubectl getinfo scheduling resources pods -o json              # Output in JSON format
+  kubectl getinfo scheduling resources pods --with-usage         # Show actual usage next to requests/limits
+  kubectl getinfo scheduling resources pods -o table --table-layout=merged   # cpu: 500m/1 (request/limit)
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
+      --with-usage                 Show actual CPU/memory usage from the metrics API (pods only, json, yaml, jsonl)
+      --table-layout <layout>      default, or merged for request/limit pairs per resource (table, csv, tsv)
   -h, --help                       Show help
 `)
 	case "topology":
@@ -253,13 +648,16 @@ Examples:
   kubectl getinfo scheduling topology pods -A                    # List topology constraints of all pods
   kubectl getinfo scheduling topology deployments -n prod       # List topology constraints of deployments
   kubectl getinfo scheduling topology pods -o json               # Output in JSON format
+  kubectl getinfo scheduling topology deployments -o table --wide  # One row per constraint
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
+      --wide                       Show maxSkew, topologyKey and whenUnsatisfiable of each constraint (table)
   -h, --help                       Show help
 `)
 	case "priority":
@@ -272,13 +670,17 @@ Examples:
   kubectl getinfo scheduling priority pods -A                    # List priority info of all pods
   kubectl getinfo scheduling priority deployments -n prod       # List priority info of deployments
   kubectl getinfo scheduling priority pods -o json               # Output in JSON format
+  kubectl getinfo scheduling priority pods -o table              # PRIORITYCLASS, VALUE and PREEMPTION columns
+  kubectl getinfo scheduling priority deployments --resolve-priority  # Value of the PriorityClass named by the template
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
+      --resolve-priority           Look up the PriorityClass for its value, globalDefault and preemptionPolicy
   -h, --help                       Show help
 `)
 	case "runtime":
@@ -291,15 +693,52 @@ Examples:
   kubectl getinfo scheduling runtime pods -A                     # List runtime info of all pods
   kubectl getinfo scheduling runtime deployments -n prod        # List runtime info of deployments
   kubectl getinfo scheduling runtime pods -o json                # Output in JSON format
+  kubectl getinfo scheduling runtime pods -o table               # RUNTIMECLASS and HOSTNET/HOSTPID/HOSTIPC columns
 
 Flags:
   -n, --namespace <namespace>      Specify namespace
   -A, --all-namespaces             All namespaces
   -l, --selector <selector>        Label selector (e.g., -l app=nginx)
-  -o, --output <format>            Output format (json, yaml). Default: yaml
+  -F, --filename <file>            Read objects from a file or stdin (-) instead of the cluster, may be repeated
+  -o, --output <format>            Output format (json, yaml, table). Default: yaml
   -c, --color                      Colorize JSON output
   -h, --help                       Show help
 `)
 	}
 }
 
+// printSnapshotUsage prints usage information for the snapshot command
+func printSnapshotUsage() {
+	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo snapshot <command> [subcommand] <resource-type> [resource-name...] [flags]
+
+Run a command and save its output to a snapshot file together with a timestamp, instead of printing it.
+Compare two snapshots with "kubectl getinfo snapshot-diff" to audit changes before and after a deployment.
+
+Examples:
+  kubectl getinfo snapshot labels pods -A                               # Save labels of all pods
+  kubectl getinfo snapshot annotations deployments -n prod              # Save annotations of deployments
+  kubectl getinfo snapshot scheduling pods --snapshot-file before.json  # Save to a specific file
+
+Flags:
+  --snapshot-file <path>           File to write. Default: getinfo-snapshot-<timestamp>.json
+  All flags of the wrapped command are supported.
+`)
+}
+
+// printSnapshotDiffUsage prints usage information for the snapshot-diff command
+func printSnapshotDiffUsage() {
+	fmt.Fprintf(os.Stdout, `Usage: kubectl getinfo snapshot-diff <old-snapshot> <new-snapshot> [flags]
+
+Compare two snapshot files and report added, removed and changed resources.
+Labels and annotations are compared key by key, other fields report which field changed.
+
+Examples:
+  kubectl getinfo snapshot-diff before.json after.json             # Human-readable report
+  kubectl getinfo snapshot-diff before.json after.json -o json     # Output in JSON format
+
+Flags:
+  -o, --output <format>            Output format (text, json, yaml). Default: text
+  -q, --quiet                      Don't warn when the snapshots were taken with different commands
+  -h, --help                       Show help
+`)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// watchListOptions returns the options of the watch request, selectors are applied by the API server
+func watchListOptions(labelSelector labels.Selector, fieldSelector string) metav1.ListOptions {
+	listOptions := metav1.ListOptions{FieldSelector: fieldSelector}
+	if labelSelector != nil {
+		listOptions.LabelSelector = labelSelector.String()
+	}
+	return listOptions
+}
+
+// watchResources calls handle for every change of the matching resources until ctx is done
+// Without a resourceVersion the API server first sends an ADDED event for each existing resource.
+// The API server closes watches after a while, the watch is then resumed from the last seen resourceVersion.
+// When that resourceVersion is too old (410 Gone), the resources are listed again and the watch starts
+// from the resourceVersion of the list.
+func watchResources(
+	ctx context.Context,
+	client dynamic.Interface,
+	gvr schema.GroupVersionResource,
+	namespace string,
+	listOptions metav1.ListOptions,
+	handle func(eventType watch.EventType, item unstructured.Unstructured) error,
+) error {
+	for {
+		watcher, err := client.Resource(gvr).Namespace(namespace).Watch(ctx, listOptions)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if apierrors.IsForbidden(err) {
+				return forbiddenError("watch", gvr, namespace)
+			}
+			if isWatchExpired(err) {
+				if listOptions.ResourceVersion, err = currentResourceVersion(ctx, client, gvr, namespace, listOptions); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("error watching resources: %v", err)
+		}
+
+		resourceVersion, err := handleWatchEvents(ctx, watcher, handle)
+		watcher.Stop()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if isWatchExpired(err) {
+			if listOptions.ResourceVersion, err = currentResourceVersion(ctx, client, gvr, namespace, listOptions); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if resourceVersion != "" {
+			listOptions.ResourceVersion = resourceVersion
+		}
+	}
+}
+
+// isWatchExpired checks if a watch failed because its resourceVersion is no longer kept by the API server
+func isWatchExpired(err error) bool {
+	return err != nil && (apierrors.IsResourceExpired(err) || apierrors.IsGone(err))
+}
+
+// currentResourceVersion lists the resources again to get a resourceVersion the watch can start from
+// A single item is asked, only the resourceVersion of the list is used.
+func currentResourceVersion(
+	ctx context.Context,
+	client dynamic.Interface,
+	gvr schema.GroupVersionResource,
+	namespace string,
+	listOptions metav1.ListOptions,
+) (string, error) {
+	listOptions.ResourceVersion = ""
+	listOptions.Limit = 1
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, listOptions)
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			return "", forbiddenError("list", gvr, namespace)
+		}
+		return "", fmt.Errorf("error listing resources to resume the watch: %w", err)
+	}
+	return list.GetResourceVersion(), nil
+}
+
+// handleWatchEvents passes the events of one watch to handle until the watch is closed or ctx is done
+// Returns the resourceVersion of the last event so the watch can be resumed
+func handleWatchEvents(
+	ctx context.Context,
+	watcher watch.Interface,
+	handle func(eventType watch.EventType, item unstructured.Unstructured) error,
+) (string, error) {
+	var resourceVersion string
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			if event.Type == watch.Error {
+				return "", fmt.Errorf("watch failed: %w", apierrors.FromObject(event.Object))
+			}
+			item, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			resourceVersion = item.GetResourceVersion()
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			if err := handle(event.Type, *item); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+// matchesWatchFilters checks the filters that are applied after listing (names, excluded namespaces)
+// against a watched resource
+func matchesWatchFilters(item unstructured.Unstructured, resourceNames []string, excludedNamespaces []string) bool {
+	if len(excludeNamespaces([]unstructured.Unstructured{item}, excludedNamespaces)) == 0 {
+		return false
+	}
+	if len(resourceNames) == 0 {
+		return true
+	}
+	for _, name := range resourceNames {
+		if item.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// printWatchEvent writes one event as a single JSON line
+func printWatchEvent(w io.Writer, eventType watch.EventType, item OutputItem) error {
+	line, err := json.Marshal(WatchEvent{Event: string(eventType), Item: item})
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(line))
+	return err
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestHandleWatchEventsWritesJSONLines(t *testing.T) {
+	watcher := watch.NewFake()
+	go func() {
+		pod := newTestPod("default", "web")
+		pod.SetLabels(map[string]string{"app": "web"})
+		pod.SetResourceVersion("10")
+		watcher.Add(pod)
+		pod = pod.DeepCopy()
+		pod.SetResourceVersion("11")
+		watcher.Delete(pod)
+		watcher.Stop()
+	}()
+
+	var buf bytes.Buffer
+	resourceVersion, err := handleWatchEvents(context.Background(), watcher, func(eventType watch.EventType, item unstructured.Unstructured) error {
+		labels := item.GetLabels()
+		return printWatchEvent(&buf, eventType, OutputItem{Name: item.GetName(), Namespace: item.GetNamespace(), Labels: &labels})
+	})
+	if err != nil {
+		t.Fatalf("handleWatchEvents() error = %v", err)
+	}
+
+	want := `{"event":"ADDED","item":{"name":"web","namespace":"default","labels":{"app":"web"}}}` + "\n" +
+		`{"event":"DELETED","item":{"name":"web","namespace":"default","labels":{"app":"web"}}}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if resourceVersion != "11" {
+		t.Errorf("resourceVersion = %q, want 11", resourceVersion)
+	}
+}
+
+func TestWatchResourcesStopsAtTimeout(t *testing.T) {
+	client := newFakeDynamicClient(newTestPod("default", "web"))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- watchResources(ctx, client, testPodGVR, "default", watchListOptions(nil, ""), func(watch.EventType, unstructured.Unstructured) error {
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("watchResources() error = %v, want nil when the timeout expires", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchResources() didn't return after the timeout")
+	}
+}
+
+func TestWatchResourcesRelistsWhenExpired(t *testing.T) {
+	client := newFakeDynamicClient()
+	client.PrependReactor("list", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{}
+		list.SetResourceVersion("500")
+		return true, list, nil
+	})
+
+	// The first watch is too old for the API server, the second one must start from the listed resourceVersion
+	var mu sync.Mutex
+	var watchedVersions []string
+	client.PrependWatchReactor("pods", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		watchedVersions = append(watchedVersions, action.(clienttesting.WatchActionImpl).WatchRestrictions.ResourceVersion)
+
+		watcher := watch.NewFake()
+		if len(watchedVersions) == 1 {
+			go watcher.Error(&apierrors.NewResourceExpired("too old resource version: 10 (400)").ErrStatus)
+		} else {
+			pod := newTestPod("default", "web")
+			pod.SetResourceVersion("501")
+			go watcher.Add(pod)
+		}
+		return true, watcher, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var events []string
+	err := watchResources(ctx, client, testPodGVR, "default", watchListOptions(nil, ""), func(eventType watch.EventType, item unstructured.Unstructured) error {
+		events = append(events, string(eventType)+" "+item.GetName())
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("watchResources() error = %v, want the watch to resume", err)
+	}
+	if want := []string{"ADDED web"}; !equalStrings(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"", "500"}; !equalStrings(watchedVersions, want) {
+		t.Errorf("watched resourceVersions = %q, want %q", watchedVersions, want)
+	}
+}
+
+func TestMatchesWatchFilters(t *testing.T) {
+	item := *newTestPod("kube-system", "coredns")
+
+	tests := []struct {
+		name     string
+		names    []string
+		excluded []string
+		want     bool
+	}{
+		{name: "no filters", want: true},
+		{name: "requested name", names: []string{"web", "coredns"}, want: true},
+		{name: "other name", names: []string{"web"}, want: false},
+		{name: "excluded namespace", excluded: systemNamespaces, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesWatchFilters(item, tt.names, tt.excluded); got != tt.want {
+				t.Errorf("matchesWatchFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}